@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"droidkfx.com/sudoku/pkg/repository"
+)
+
+// checkBoards checks the board repositories in the given directories and prints what is wrong with them, it exits
+// with 1 if a board can not be used, see repository.CheckSudokuBoardRepo
+func main() {
+	quarantine := flag.Bool("quarantine", false, "move boards that can not be used to boards.quarantine")
+	flag.Parse()
+	dirs := flag.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"./data"}
+	}
+
+	bad := false
+	for _, dir := range dirs {
+		report, err := repository.CheckSudokuBoardRepo(dir, *quarantine)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", dir, err)
+			bad = true
+			continue
+		}
+		for _, f := range report.Findings {
+			fmt.Printf("%s: %s\n", dir, f)
+		}
+		fmt.Printf("%s: %d records, %d removed, %d problems, %d quarantined\n", dir, report.Records, report.Removed,
+			len(report.Findings), len(report.Quarantined))
+		bad = bad || (report.Bad() && !*quarantine)
+	}
+	if bad {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+
+	"droidkfx.com/sudoku/pkg/repository"
+)
+
+// compactBoards removes the tombstones of deleted boards from the repository and prints the old and new id of every
+// board that is left, one pair per line, see repository.CompactSudokuBoardRepo. The repository must not be in use.
+func main() {
+	dataDir := flag.String("data", "./data", "directory of the board repository to compact")
+	flag.Parse()
+
+	newIds, err := repository.CompactSudokuBoardRepo(*dataDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	oldIds := make([]int, 0, len(newIds))
+	for oldId := range newIds {
+		oldIds = append(oldIds, oldId)
+	}
+	slices.Sort(oldIds)
+	for _, oldId := range oldIds {
+		fmt.Printf("%d\t%d\n", oldId, newIds[oldId])
+	}
+	fmt.Fprintf(os.Stderr, "%d boards kept\n", len(newIds))
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/repository"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+// listBoards prints the boards of the repository one per line with their id, difficulty, clues and source, the flags
+// select which boards to print
+func main() {
+	dataDir := flag.String("data", "./data", "directory of the board repository to list")
+	offset := flag.Int("offset", 0, "id of the first board to list")
+	limit := flag.Int("limit", 0, "most boards to list, 0 lists all of them")
+	difficulty := flag.String("difficulty", "", "only list boards of this difficulty, e.g. medium")
+	technique := flag.String("technique", "", "only list boards whose solve path uses this strategy, e.g. XWing")
+	minClues := flag.Int("min-clues", 0, "only list boards with at least this many clues")
+	maxClues := flag.Int("max-clues", 0, "only list boards with at most this many clues, 0 for any number")
+	flag.Parse()
+
+	query := repository.Query{MinClues: *minClues, MaxClues: *maxClues}
+	if *difficulty != "" {
+		d, err := solver.ParseStrategyDifficulty(*difficulty)
+		if err != nil {
+			fail(err)
+		}
+		query.Difficulties = append(query.Difficulties, d)
+	}
+	if *technique != "" {
+		name, err := solver.ParseStrategyName(*technique)
+		if err != nil {
+			fail(err)
+		}
+		query.Technique = name
+	}
+
+	r, sd, err := repository.NewSudokuBoardRepo(*dataDir)
+	if err != nil {
+		fail(err)
+	}
+	defer sd()
+
+	listed := 0
+	err = r.Each(*offset, func(stored repository.StoredBoard) bool {
+		if !query.Matches(stored.Metadata) {
+			return true
+		}
+		difficulty := "unrated"
+		if stored.Metadata.Unique {
+			difficulty = stored.Metadata.Rating.Difficulty.String()
+		}
+		fmt.Printf("%d\t%s\t%s\t%d\t%s\n", stored.Id, board.FormatLine(stored.Board), difficulty,
+			stored.Metadata.Clues, stored.Metadata.Source)
+		listed++
+		return *limit == 0 || listed < *limit
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -8,12 +8,20 @@ import (
 )
 
 func main() {
-	// r, sd := repository.NewSudokuBoardRepo("./data/nyt/med")
-	// r, sd := repository.NewSudokuBoardRepo("./data/nyt/easy")
-	r, sd := repository.NewSudokuBoardRepo("./data")
+	// r, sd, err := repository.NewSudokuBoardRepo("./data/nyt/med")
+	// r, sd, err := repository.NewSudokuBoardRepo("./data/nyt/easy")
+	r, sd, err := repository.NewSudokuBoardRepo("./data")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 	defer sd()
 
-	_, b := r.GetByNumber(50)
+	_, b, err := r.GetByNumber(50)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 	fmt.Println(b)
 	solution := solver.SolveByStrategies(b)
 	for _, step := range solution {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"droidkfx.com/sudoku/pkg/board"
@@ -10,7 +11,11 @@ import (
 )
 
 func main() {
-	r, sd := repository.NewSudokuBoardRepo("./data")
+	r, sd, err := repository.NewSudokuBoardRepo("./data")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 	defer sd()
 
 	// Generate all permutations of the numbers 1-9
@@ -46,13 +51,65 @@ func main() {
 	}
 
 	fmt.Printf("Boards generated: %d (%d/s)\r\n", len(perms), int(float64(len(perms))/totalTime.Seconds()))
+	boards = unique(boards)
+	fmt.Printf("Unique boards: %d\n", len(boards))
 	fmt.Print("Saving Boards...")
-	r.SaveAll(boards)
+	if err := r.SaveAllFrom("makeBoards", boards); err != nil {
+		fmt.Println()
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
 	fmt.Println("Done!")
 	totalTime = time.Since(startTime)
 	fmt.Printf("Total time: %v\n", totalTime)
 }
 
+/*
+unique drops the boards that are a rotation, reflection or relabeling of an earlier one. Boards are first compared with
+their numbers relabeled in the order they are read, which is cheap, as the guessing orders mostly give relabeled copies
+of the same grid. Only the first board of each of those groups needs its canonical form.
+*/
+func unique(boards []*board.SudokuBoard) []*board.SudokuBoard {
+	relabeled := map[string]bool{}
+	hashes := map[uint64]bool{}
+	var result []*board.SudokuBoard
+	for _, b := range boards {
+		key := relabelKey(b)
+		if relabeled[key] {
+			continue
+		}
+		relabeled[key] = true
+
+		hash, err := board.CanonicalHash(b)
+		if err != nil {
+			panic(err)
+		}
+		if !hashes[hash] {
+			hashes[hash] = true
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+// relabelKey is the board with its numbers renamed 1, 2, 3... in the order they first appear
+func relabelKey(b *board.SudokuBoard) string {
+	labels := [10]int{}
+	next := 1
+	key := make([]byte, 0, 81)
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			v := b.GetAt(x, y)
+			if v != 0 && labels[v] == 0 {
+				labels[v] = next
+				next++
+			}
+			key = append(key, byte('0'+labels[v]))
+		}
+	}
+	return string(key)
+}
+
 func permutations(arr []int) [][]int {
 	var helper func([]int, int)
 	res := [][]int{}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/generator"
+	"droidkfx.com/sudoku/pkg/repository"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+// makePuzzles generates a set of puzzles with a fixed mix of difficulties and saves them to the repository
+func main() {
+	dataDir := flag.String("data", "./data", "directory of the board repository to save to")
+	easy := flag.Int("easy", 0, "number of easy puzzles")
+	medium := flag.Int("medium", 0, "number of medium puzzles")
+	hard := flag.Int("hard", 0, "number of hard puzzles")
+	strategy := flag.String("strategy", "", "generate -count puzzles that need this strategy and nothing harder, e.g. XWing")
+	count := flag.Int("count", 1, "number of puzzles for -strategy")
+	symmetry := flag.String("symmetry", "none", "symmetry of the clues: none, rotational180, rotational90, mirror or diagonal")
+	attempts := flag.Int("attempts", 10000, "attempts per puzzle before giving up")
+	flag.Parse()
+
+	s, err := generator.ParseSymmetry(*symmetry)
+	if err != nil {
+		fail(err)
+	}
+	type order struct {
+		target generator.Target
+		count  int
+	}
+	orders := []order{
+		{target: generator.TargetDifficulty(solver.StrategyDifficultyEasy), count: *easy},
+		{target: generator.TargetDifficulty(solver.StrategyDifficultyMedium), count: *medium},
+		{target: generator.TargetDifficulty(solver.StrategyDifficultyHard), count: *hard},
+	}
+	if *strategy != "" {
+		name, err := solver.ParseStrategyName(*strategy)
+		if err != nil {
+			fail(err)
+		}
+		orders = append(orders, order{target: generator.TargetStrategy(name), count: *count})
+	}
+
+	var puzzles []*board.SudokuBoard
+	for _, o := range orders {
+		o.target.Symmetry = s
+		for i := 0; i < o.count; i++ {
+			generated, err := generator.GenerateTargetPuzzle(o.target, *attempts)
+			if err != nil {
+				fail(err)
+			}
+			fmt.Printf("%s %v in %d attempts\n", board.FormatLine(generated.Puzzle), generated.Rating.Strategies,
+				generated.Attempts)
+			puzzles = append(puzzles, generated.Puzzle)
+		}
+	}
+
+	r, sd, err := repository.NewSudokuBoardRepo(*dataDir)
+	if err != nil {
+		fail(err)
+	}
+	if err := errors.Join(r.SaveAllFrom("makePuzzles", puzzles), sd()); err != nil {
+		fail(err)
+	}
+	fmt.Printf("Saved %d boards\n", len(puzzles))
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
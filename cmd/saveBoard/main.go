@@ -1,25 +1,148 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
 	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/generator"
 	"droidkfx.com/sudoku/pkg/repository"
+	"droidkfx.com/sudoku/pkg/solver"
 )
 
+// saveBoard reads puzzles from the given files and saves them to the repository, see readBoards for the formats
 func main() {
-	r, sd := repository.NewSudokuBoardRepo("./data/nyt/med")
-	defer sd()
-
-	b := board.FromNumbers([9][9]int{
-		{0, 0, 0, 4, 0, 1, 6, 0, 0},
-		{0, 0, 2, 5, 0, 0, 0, 0, 3},
-		{5, 3, 0, 9, 0, 0, 0, 0, 0},
-		{0, 0, 6, 0, 0, 0, 3, 0, 0},
-		{0, 0, 0, 0, 8, 0, 0, 4, 1},
-		{4, 0, 5, 0, 0, 0, 0, 0, 7},
-		{2, 5, 8, 0, 0, 9, 0, 0, 0},
-		{0, 0, 9, 0, 4, 2, 0, 0, 0},
-		{0, 0, 0, 7, 0, 0, 0, 0, 0},
-	})
-
-	r.SaveNew(b)
+	dataDir := flag.String("data", "./data", "directory of the board repository to save to")
+	repair := flag.Bool("repair", false, "add clues to puzzles with several solutions and skip those without any")
+	minimize := flag.Bool("minimize", false, "remove the givens puzzles do not need")
+	maxClues := flag.Int("max-clues", 3, "most clues -repair adds to a puzzle")
+	source := flag.String("source", "", "collection the puzzles come from, e.g. nyt/easy")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: saveBoard [-data dir] [-source name] [-repair] [-minimize] file...")
+		os.Exit(2)
+	}
+
+	var boards []*board.SudokuBoard
+	for _, name := range flag.Args() {
+		content, err := os.ReadFile(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		read, err := readBoards(name, string(content))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			os.Exit(1)
+		}
+		boards = append(boards, read...)
+	}
+	if *repair || *minimize {
+		boards = fixBoards(boards, *repair, *minimize, *maxClues)
+	}
+
+	r, sd, err := repository.NewSudokuBoardRepo(*dataDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := errors.Join(r.SaveAllFrom(*source, boards), sd()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Saved %d boards\n", len(boards))
+}
+
+/*
+fixBoards repairs and minimizes the boards as asked and reports what it did for each of them. With repair the boards
+that can not be repaired are left out, without it every board is kept. Only boards with a single solution are
+minimized.
+*/
+func fixBoards(boards []*board.SudokuBoard, repair, minimize bool, maxClues int) []*board.SudokuBoard {
+	var result []*board.SudokuBoard
+	for i, b := range boards {
+		solutions := solver.FindSolutions(b, 2)
+		if len(solutions) == 0 && repair {
+			fmt.Printf("board %d: no solution, skipped\n", i+1)
+			continue
+		}
+		if len(solutions) == 2 && repair {
+			fmt.Printf("board %d: several solutions, two of them differ at %v\n", i+1,
+				board.Diff(solutions[0], solutions[1]))
+			clues, err := generator.SuggestClues(b, maxClues)
+			if err != nil {
+				fmt.Printf("board %d: %v, skipped\n", i+1, err)
+				continue
+			}
+			b = b.Copy()
+			for _, c := range clues {
+				b.SetAt(c.X, c.Y, c.Value)
+			}
+			fmt.Printf("board %d: added %v\n", i+1, clues)
+		}
+		if minimize {
+			if minimal, err := generator.Minimize(b); err == nil {
+				fmt.Printf("board %d: removed %d givens\n", i+1, len(board.Diff(b, minimal)))
+				b = minimal
+			}
+		}
+		result = append(result, b)
+	}
+	return result
+}
+
+/*
+readBoards picks the format from the file extension, .sdk for SadMan files and .ss for Simple Sudoku files. Any other
+file holds a single board in a format board.ParseBoard reads or a collection with one 81 character board per line. A
+file is only read as a collection if a line starts with at least 81 characters, otherwise the error of ParseBoard is
+returned so a grid with a bad cell is reported where the cell is.
+*/
+func readBoards(name, content string) ([]*board.SudokuBoard, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".sdk":
+		b, err := board.ParseSDK(content)
+		return []*board.SudokuBoard{b}, err
+	case ".ss":
+		b, err := board.ParseSS(content)
+		return []*board.SudokuBoard{b}, err
+	}
+
+	b, err := board.ParseBoard(content)
+	if err == nil {
+		return []*board.SudokuBoard{b}, nil
+	}
+	lines := strings.Split(content, "\n")
+	if !slices.ContainsFunc(lines, func(line string) bool {
+		return !skipLine(line) && utf8.RuneCountInString(strings.Fields(line)[0]) >= 81
+	}) {
+		return nil, err
+	}
+
+	var boards []*board.SudokuBoard
+	for i, line := range lines {
+		if skipLine(line) {
+			continue
+		}
+		b, err := board.ParseLine(line)
+		if err != nil {
+			var parseErr *board.ParseError
+			if errors.As(err, &parseErr) {
+				parseErr.Line = i + 1
+			}
+			return nil, err
+		}
+		boards = append(boards, b)
+	}
+	return boards, nil
+}
+
+// skipLine is true for the blank and comment lines of a collection
+func skipLine(line string) bool {
+	return strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#")
 }
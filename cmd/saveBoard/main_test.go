@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/board"
+)
+
+const testLine = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+
+func Test_readBoards(t *testing.T) {
+	boxed := "+-------+-------+-------+\n" +
+		"| 5 3 _ | _ 7 _ | _ _ _ |\n" +
+		"| 6 _ _ | 1 9 5 | _ _ _ |\n" +
+		"| _ 9 8 | _ _ _ | _ 6 _ |\n" +
+		"+-------+-------+-------+\n" +
+		"| 8 _ _ | _ 6 _ | _ _ 3 |\n" +
+		"| 4 _ _ | 8 x 3 | _ _ 1 |\n" +
+		"| 7 _ _ | _ 2 _ | _ _ 6 |\n" +
+		"+-------+-------+-------+\n" +
+		"| _ 6 _ | _ _ _ | 2 8 _ |\n" +
+		"| _ _ _ | 4 1 9 | _ _ 5 |\n" +
+		"| _ _ _ | _ 8 _ | _ 7 9 |\n" +
+		"+-------+-------+-------+\n"
+	tests := []struct {
+		name       string
+		content    string
+		want       int
+		wantLine   int
+		wantColumn int
+	}{
+		{name: "board", content: board.FormatGrid(board.FromNumbers([9][9]int{{1, 2, 3}})), want: 1},
+		{name: "collection", content: "# puzzles\n" + testLine + "  rated 1.2\n\n" + testLine + "\n", want: 2},
+		{name: "collection with a bad board", content: testLine + "\n" + testLine[:80] + "\n", wantLine: 2, wantColumn: 81},
+		{name: "boxed grid with a bad cell", content: boxed, wantLine: 7, wantColumn: 13},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readBoards("puzzles.txt", tt.content)
+			var parseErr *board.ParseError
+			if tt.wantLine == 0 && (err != nil || len(got) != tt.want) {
+				t.Errorf("readBoards() = %d boards, %v, want %d", len(got), err, tt.want)
+			} else if tt.wantLine != 0 && (!errors.As(err, &parseErr) || parseErr.Line != tt.wantLine ||
+				parseErr.Column != tt.wantColumn) {
+				t.Errorf("readBoards() error = %v, want line %d, column %d", err, tt.wantLine, tt.wantColumn)
+			}
+		})
+	}
+	if got, _ := readBoards("puzzles.txt", strings.Repeat(testLine+"\n", 3)); len(got) != 3 {
+		t.Errorf("readBoards() = %d boards, want 3", len(got))
+	}
+}
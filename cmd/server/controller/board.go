@@ -2,11 +2,14 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"droidkfx.com/sudoku/pkg/board"
 	"droidkfx.com/sudoku/pkg/repository"
+	"droidkfx.com/sudoku/pkg/solver"
 )
 
 func RegisterBoardHandlers(mux *http.ServeMux, r repository.SudokuBoardRepo) {
@@ -15,13 +18,70 @@ func RegisterBoardHandlers(mux *http.ServeMux, r repository.SudokuBoardRepo) {
 	}
 
 	mux.HandleFunc("GET /board/random", c.GetRandomBoard)
+	mux.HandleFunc("GET /board/list", c.ListBoards)
 	mux.HandleFunc("GET /board/{id}", c.GetBoardById)
 }
 
 type GetBoardByIdResponse struct {
-	Id         int       `json:"id"`
-	Difficulty string    `json:"difficulty"`
-	Board      [9][9]int `json:"board"`
+	Id         int                 `json:"id"`
+	Difficulty string              `json:"difficulty"`
+	Board      *board.SudokuBoard  `json:"board"`
+	Metadata   repository.Metadata `json:"metadata"`
+}
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+type ListBoardsResponse struct {
+	Total  int                    `json:"total"`
+	Offset int                    `json:"offset"`
+	Boards []GetBoardByIdResponse `json:"boards"`
+}
+
+/*
+ListBoards pages through the boards selected by the query parameters of parseQuery, ?offset= is the number of them to
+skip and ?limit= the page size, at most maxListLimit.
+*/
+func (b *boardController) ListBoards(writer http.ResponseWriter, request *http.Request) {
+	query, err := parseQuery(request)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	offset, limit := 0, defaultListLimit
+	if text := request.URL.Query().Get("offset"); text != "" {
+		if offset, err = strconv.Atoi(text); err != nil || offset < 0 {
+			http.Error(writer, "offset must be a number of at least 0", http.StatusBadRequest)
+			return
+		}
+	}
+	if text := request.URL.Query().Get("limit"); text != "" {
+		if limit, err = strconv.Atoi(text); err != nil || limit < 1 || limit > maxListLimit {
+			http.Error(writer, fmt.Sprintf("limit must be a number from 1 to %d", maxListLimit), http.StatusBadRequest)
+			return
+		}
+	}
+
+	total, err := b.r.CountMatching(query)
+	if err != nil {
+		writeRepositoryError(writer, err)
+		return
+	}
+	stored, err := b.r.Find(query, offset, limit)
+	if err != nil {
+		writeRepositoryError(writer, err)
+		return
+	}
+
+	response := ListBoardsResponse{Total: total, Offset: offset, Boards: []GetBoardByIdResponse{}}
+	for _, sb := range stored {
+		response.Boards = append(response.Boards, b.toBoardResponse(sb.Id, sb.Board, sb.Metadata))
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(writer).Encode(response)
 }
 
 func (b *boardController) GetBoardById(writer http.ResponseWriter, request *http.Request) {
@@ -31,35 +91,102 @@ func (b *boardController) GetBoardById(writer http.ResponseWriter, request *http
 		return
 	}
 
-	writer.Header().Set("Content-Type", "application/json")
-	writer.WriteHeader(http.StatusOK)
-	idGot, nBoard := b.r.GetByNumber(id)
-	_ = json.NewEncoder(writer).Encode(GetBoardByIdResponse{
-		Id:         idGot,
-		Difficulty: "TBD",
-		Board:      b.SudokuBoardToResponseBoard(nBoard),
-	})
+	stored, err := b.r.GetStored(id)
+	if err != nil {
+		writeRepositoryError(writer, err)
+		return
+	}
+	b.writeBoard(writer, stored)
+}
+
+// GetRandomBoard returns one of the boards selected by the query parameters of parseQuery
+func (b *boardController) GetRandomBoard(writer http.ResponseWriter, request *http.Request) {
+	query, err := parseQuery(request)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	stored, err := b.r.GetRandomStored(query)
+	if err != nil {
+		writeRepositoryError(writer, err)
+		return
+	}
+	b.writeBoard(writer, stored)
 }
 
-func (b *boardController) GetRandomBoard(writer http.ResponseWriter, _ *http.Request) {
+func (b *boardController) writeBoard(writer http.ResponseWriter, stored repository.StoredBoard) {
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(http.StatusOK)
-	idGot, rBoard := b.r.GetRandom()
-	_ = json.NewEncoder(writer).Encode(GetBoardByIdResponse{
-		Id:         idGot,
-		Difficulty: "TBD",
-		Board:      b.SudokuBoardToResponseBoard(rBoard),
-	})
+	_ = json.NewEncoder(writer).Encode(b.toBoardResponse(stored.Id, stored.Board, stored.Metadata))
+}
+
+func (b *boardController) toBoardResponse(id int, brd *board.SudokuBoard,
+	metadata repository.Metadata) GetBoardByIdResponse {
+	return GetBoardByIdResponse{
+		Id:         id,
+		Difficulty: b.MetadataToResponseDifficulty(metadata),
+		Board:      brd,
+		Metadata:   metadata,
+	}
 }
 
-func (b *boardController) SudokuBoardToResponseBoard(brd *board.SudokuBoard) [9][9]int {
-	data := [9][9]int{}
-	for i := 0; i < 9; i++ {
-		for j := 0; j < 9; j++ {
-			data[j][i] = brd.GetAt(i, j)
+/*
+parseQuery reads the repository query of the request, every parameter is optional. ?difficulty= may be given more than
+once and takes names such as "medium", ?technique= is the name of a strategy such as "XWing" and ?minClues= and
+?maxClues= bound the number of clues. A maxClues of 0 is refused, the repository reads it as no bound.
+*/
+func parseQuery(request *http.Request) (repository.Query, error) {
+	values := request.URL.Query()
+	query := repository.Query{}
+	for _, name := range values["difficulty"] {
+		d, err := solver.ParseStrategyDifficulty(name)
+		if err != nil {
+			return query, err
 		}
+		query.Difficulties = append(query.Difficulties, d)
+	}
+	if name := values.Get("technique"); name != "" {
+		technique, err := solver.ParseStrategyName(name)
+		if err != nil {
+			return query, err
+		}
+		query.Technique = technique
+	}
+	bounds := []struct {
+		key    string
+		lowest int
+		bound  *int
+	}{{"minClues", 0, &query.MinClues}, {"maxClues", 1, &query.MaxClues}}
+	for _, bound := range bounds {
+		if text := values.Get(bound.key); text != "" {
+			clues, err := strconv.Atoi(text)
+			if err != nil || clues < bound.lowest || clues > 81 {
+				return query, fmt.Errorf("%s must be a number from %d to 81", bound.key, bound.lowest)
+			}
+			*bound.bound = clues
+		}
+	}
+	if query.MaxClues != 0 && query.MinClues > query.MaxClues {
+		return query, fmt.Errorf("minClues %d is above maxClues %d", query.MinClues, query.MaxClues)
+	}
+	return query, nil
+}
+
+// writeRepositoryError answers 404 for boards that do not exist, other errors of the repository are the server's fault
+func writeRepositoryError(writer http.ResponseWriter, err error) {
+	if errors.Is(err, repository.ErrNotFound) {
+		http.Error(writer, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(writer, err.Error(), http.StatusInternalServerError)
+}
+
+// MetadataToResponseDifficulty names the difficulty of the board, boards without a single solution are not rated
+func (b *boardController) MetadataToResponseDifficulty(metadata repository.Metadata) string {
+	if !metadata.Unique {
+		return "unrated"
 	}
-	return data
+	return metadata.Rating.Difficulty.String()
 }
 
 type boardController struct {
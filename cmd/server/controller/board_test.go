@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/repository"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+func Test_parseQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    repository.Query
+		wantErr bool
+	}{
+		{name: "empty", query: ""},
+		{name: "difficulties", query: "difficulty=easy&difficulty=hard",
+			want: repository.Query{Difficulties: []solver.StrategyDifficulty{solver.StrategyDifficultyEasy,
+				solver.StrategyDifficultyHard}}},
+		{name: "clue range", query: "minClues=0&maxClues=30", want: repository.Query{MaxClues: 30}},
+		{name: "no clues", query: "maxClues=0", wantErr: true},
+		{name: "too many clues", query: "minClues=82", wantErr: true},
+		{name: "empty clue range", query: "minClues=30&maxClues=20", wantErr: true},
+		{name: "unknown difficulty", query: "difficulty=trivial", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseQuery(httptest.NewRequest(http.MethodGet, "/board/list?"+tt.query, nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseQuery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/generator"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+// generateTimeout limits how long a request can take, hard puzzles take about ten seconds on average
+const generateTimeout = 60 * time.Second
+
+func RegisterGeneratorHandlers(mux *http.ServeMux) {
+	c := newGeneratorController()
+
+	mux.HandleFunc("GET /board/generate", c.GenerateBoard)
+	mux.HandleFunc("GET /board/daily", c.GetDailyBoard)
+}
+
+// dailyCacheSize bounds the daily puzzles kept, a few days of every difficulty
+const dailyCacheSize = 64
+
+type generatorController struct {
+	// the lock guards daily, it is not held while a puzzle is generated
+	lock  sync.Mutex
+	daily map[string]*dailyPuzzle
+	// generating holds a token for each GenerateBoard search running, one per CPU as each keeps a CPU busy
+	generating chan struct{}
+}
+
+func newGeneratorController() *generatorController {
+	return &generatorController{
+		daily:      map[string]*dailyPuzzle{},
+		generating: make(chan struct{}, runtime.GOMAXPROCS(0)),
+	}
+}
+
+/*
+dailyPuzzle is the puzzle of a date and difficulty, generated once for the first request asking for it while the
+requests for the same puzzle wait for done. A failure is kept like a puzzle, generating it again would fail the same
+way as the generator is seeded. A search stopped by generateTimeout, or cancelled as every request waiting for it
+left, is forgotten instead so the next request searches again.
+*/
+type dailyPuzzle struct {
+	done     chan struct{}
+	response GenerateBoardResponse
+	err      error
+	// waiting counts the requests waiting for done, guarded by the lock of the controller
+	waiting int
+	cancel  context.CancelFunc
+}
+
+type GenerateBoardResponse struct {
+	Board      *board.SudokuBoard    `json:"board"`
+	Difficulty string                `json:"difficulty"`
+	Strategies []solver.StrategyName `json:"strategies"`
+	Attempts   int                   `json:"attempts"`
+	Trace      *generator.Trace      `json:"trace,omitempty"`
+	Date       string                `json:"date,omitempty"`
+}
+
+/*
+GenerateBoard makes a new puzzle. The query can ask for a difficulty, a strategy the puzzle has to need and the symmetry
+of its clues, for example ?difficulty=hard&symmetry=rotational180 or ?strategy=XWing. With trace=true the response
+includes how the puzzle was made, see generator.Trace. When every CPU is already searching the request is refused
+with 503 rather than queued.
+*/
+func (c *generatorController) GenerateBoard(writer http.ResponseWriter, request *http.Request) {
+	target, err := parseTarget(request)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	select {
+	case c.generating <- struct{}{}:
+		defer func() { <-c.generating }()
+	default:
+		http.Error(writer, "too many puzzles are being generated, try again later", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(request.Context(), generateTimeout)
+	defer cancel()
+	generated, err := generator.GenerateTargetPuzzleContext(ctx, target)
+	if errors.Is(err, generator.ErrTargetNotReached) {
+		http.Error(writer, err.Error(), http.StatusServiceUnavailable)
+		return
+	} else if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeGenerated(writer, toGenerateBoardResponse(generated))
+}
+
+/*
+GetDailyBoard returns the puzzle of the day, every player asking for the same date and difficulty gets the same
+puzzle. The date is given as ?date=2006-01-02 and defaults to today in UTC, the difficulty defaults to medium.
+*/
+func (c *generatorController) GetDailyBoard(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	date := time.Now().UTC()
+	if text := query.Get("date"); text != "" {
+		parsed, err := time.Parse(time.DateOnly, text)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		date = parsed
+	}
+	difficulty := solver.StrategyDifficultyMedium
+	if name := query.Get("difficulty"); name != "" {
+		parsed, err := solver.ParseStrategyDifficulty(name)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		difficulty = parsed
+	}
+
+	if err := generator.TargetDifficulty(difficulty).Check(); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	response, err := c.dailyBoard(request.Context(), date, difficulty)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	writeGenerated(writer, response)
+}
+
+/*
+dailyBoard returns the daily puzzle, starting to generate it unless it is known or being generated already. It waits
+until the puzzle is there or ctx is done, the search is cancelled once no request waits for it.
+*/
+func (c *generatorController) dailyBoard(ctx context.Context, date time.Time,
+	difficulty solver.StrategyDifficulty) (GenerateBoardResponse, error) {
+	key := date.Format(time.DateOnly) + "/" + difficulty.String()
+	c.lock.Lock()
+	daily, found := c.daily[key]
+	if !found {
+		c.evictDaily()
+		search, cancel := context.WithTimeout(context.Background(), generateTimeout)
+		daily = &dailyPuzzle{done: make(chan struct{}), cancel: cancel}
+		c.daily[key] = daily
+		go c.generateDaily(search, key, daily, date, difficulty)
+	}
+	daily.waiting++
+	c.lock.Unlock()
+
+	select {
+	case <-daily.done:
+		return daily.response, daily.err
+	case <-ctx.Done():
+		c.lock.Lock()
+		daily.waiting--
+		if daily.waiting == 0 {
+			daily.cancel()
+			c.forgetDaily(key, daily)
+		}
+		c.lock.Unlock()
+		return GenerateBoardResponse{}, ctx.Err()
+	}
+}
+
+// generateDaily searches for the daily puzzle and closes done, a search stopped by ctx is not kept
+func (c *generatorController) generateDaily(ctx context.Context, key string, daily *dailyPuzzle, date time.Time,
+	difficulty solver.StrategyDifficulty) {
+	generated, err := generator.DailyContext(ctx, date, difficulty)
+	daily.cancel()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err != nil {
+		daily.err = err
+		if ctx.Err() != nil {
+			c.forgetDaily(key, daily)
+		}
+	} else {
+		daily.response = toGenerateBoardResponse(generated)
+		daily.response.Date = date.Format(time.DateOnly)
+	}
+	close(daily.done)
+}
+
+// forgetDaily removes the daily puzzle unless another search took its place already, c.lock must be held
+func (c *generatorController) forgetDaily(key string, daily *dailyPuzzle) {
+	if c.daily[key] == daily {
+		delete(c.daily, key)
+	}
+}
+
+// evictDaily forgets the finished daily puzzles once there are dailyCacheSize, c.lock must be held
+func (c *generatorController) evictDaily() {
+	if len(c.daily) < dailyCacheSize {
+		return
+	}
+	for key, daily := range c.daily {
+		select {
+		case <-daily.done:
+			delete(c.daily, key)
+		default: // still being generated, requests are waiting for it
+		}
+	}
+}
+
+func toGenerateBoardResponse(generated *generator.Generated) GenerateBoardResponse {
+	return GenerateBoardResponse{
+		Board:      generated.Puzzle,
+		Difficulty: generated.Rating.Difficulty.String(),
+		Strategies: generated.Rating.Strategies,
+		Attempts:   generated.Attempts,
+		Trace:      generated.Trace,
+	}
+}
+
+func writeGenerated(writer http.ResponseWriter, response GenerateBoardResponse) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(writer).Encode(response)
+}
+
+/*
+parseTarget reads the target from the query, any difficulty up to hard is accepted if none is asked for. Targets that
+can not be generated are refused so they do not tie up the server searching, see generator.Target.Check.
+*/
+func parseTarget(request *http.Request) (generator.Target, error) {
+	query := request.URL.Query()
+	target := generator.Target{MinDifficulty: solver.StrategyDifficultyEasy, MaxDifficulty: solver.StrategyDifficultyHard}
+	if name := query.Get("strategy"); name != "" {
+		strategy, err := solver.ParseStrategyName(name)
+		if err != nil {
+			return target, err
+		}
+		target = generator.TargetStrategy(strategy)
+	}
+	if name := query.Get("difficulty"); name != "" {
+		difficulty, err := solver.ParseStrategyDifficulty(name)
+		if err != nil {
+			return target, err
+		}
+		target.MinDifficulty, target.MaxDifficulty = difficulty, difficulty
+	}
+	if name := query.Get("symmetry"); name != "" {
+		symmetry, err := generator.ParseSymmetry(name)
+		if err != nil {
+			return target, err
+		}
+		target.Symmetry = symmetry
+	}
+	target.Trace = query.Get("trace") == "true"
+	return target, target.Check()
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+func TestGenerateBoard_Refused(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "constraint strategy", query: "strategy=Thermometer"},
+		{name: "guessing", query: "strategy=Psychic"},
+		{name: "very hard", query: "difficulty=veryHard"},
+		{name: "strategy harder than the difficulty", query: "strategy=XWing&difficulty=easy"},
+		{name: "unknown strategy", query: "strategy=Guess"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/board/generate?"+tt.query, nil)
+			newGeneratorController().GenerateBoard(recorder, request)
+			if recorder.Code != http.StatusBadRequest {
+				t.Errorf("GenerateBoard() status = %d, want %d", recorder.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestGenerateBoard_Busy(t *testing.T) {
+	c := newGeneratorController()
+	for i := 0; i < cap(c.generating); i++ {
+		c.generating <- struct{}{}
+	}
+	recorder := httptest.NewRecorder()
+	c.GenerateBoard(recorder, httptest.NewRequest(http.MethodGet, "/board/generate?difficulty=easy", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("GenerateBoard() status = %d while every search is taken, want %d", recorder.Code,
+			http.StatusServiceUnavailable)
+	}
+
+	<-c.generating
+	recorder = httptest.NewRecorder()
+	c.GenerateBoard(recorder, httptest.NewRequest(http.MethodGet, "/board/generate?difficulty=easy", nil))
+	if recorder.Code != http.StatusOK || len(c.generating) != cap(c.generating)-1 {
+		t.Errorf("GenerateBoard() status = %d with %d searches taken, want %d", recorder.Code, len(c.generating),
+			http.StatusOK)
+	}
+}
+
+func TestGeneratorController_dailyBoard(t *testing.T) {
+	c := newGeneratorController()
+	day := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+	// a request that leaves cancels the search, the next request searches again
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.dailyBoard(ctx, day, solver.StrategyDifficultyHard); !errors.Is(err, context.Canceled) {
+		t.Errorf("dailyBoard() error = %v, want %v", err, context.Canceled)
+	}
+	c.lock.Lock()
+	kept := len(c.daily)
+	c.lock.Unlock()
+	if kept != 0 {
+		t.Errorf("dailyBoard() kept %d cancelled searches", kept)
+	}
+
+	first, err := c.dailyBoard(context.Background(), day, solver.StrategyDifficultyEasy)
+	if err != nil {
+		t.Fatalf("dailyBoard() error = %v", err)
+	}
+	second, _ := c.dailyBoard(context.Background(), day, solver.StrategyDifficultyEasy)
+	if board.FormatLine(first.Board) != board.FormatLine(second.Board) || first.Date != "2026-10-19" {
+		t.Errorf("dailyBoard() = %s on %s then %s", board.FormatLine(first.Board), first.Date,
+			board.FormatLine(second.Board))
+	}
+}
@@ -9,16 +9,21 @@ import (
 )
 
 func main() {
-	r, sd := repository.NewSudokuBoardRepo("./data")
+	r, sd, err := repository.NewSudokuBoardRepo("./data")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 	defer sd()
 
 	mux := http.NewServeMux()
 	controller.RegisterHealthHandlers(mux)
 	controller.RegisterBoardHandlers(mux, r)
+	controller.RegisterGeneratorHandlers(mux)
 	mux.Handle("/", http.FileServer(http.Dir("./web")))
 
 	fmt.Println("Starting server, access at http://localhost:8080")
-	err := http.ListenAndServe(":8080", mux)
+	err = http.ListenAndServe(":8080", mux)
 
 	if err != nil {
 		fmt.Println(err)
@@ -3,7 +3,8 @@ package board
 import "fmt"
 
 type SudokuBoard struct {
-	board [9][9]int
+	board       [9][9]int
+	constraints []Constraint
 }
 
 func FromNumbers(data [9][9]int) *SudokuBoard {
@@ -24,6 +25,16 @@ func (s *SudokuBoard) SetAt(x, y, value int) {
 	s.board[y][x] = value
 }
 
+// AddConstraints places additional rules on the board, see Constraint
+func (s *SudokuBoard) AddConstraints(constraints ...Constraint) *SudokuBoard {
+	s.constraints = append(s.constraints, constraints...)
+	return s
+}
+
+func (s *SudokuBoard) Constraints() []Constraint {
+	return s.constraints
+}
+
 func (s *SudokuBoard) String() string {
 	result := ""
 	for _, list := range s.board {
@@ -40,5 +51,10 @@ func (s *SudokuBoard) Copy() *SudokuBoard {
 	for i := 0; i < 9; i++ {
 		copy(board[i][:], s.board[i][:])
 	}
-	return &SudokuBoard{board: board}
+	var constraints []Constraint
+	if s.constraints != nil {
+		constraints = make([]Constraint, len(s.constraints))
+		copy(constraints, s.constraints)
+	}
+	return &SudokuBoard{board: board, constraints: constraints}
 }
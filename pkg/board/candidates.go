@@ -0,0 +1,97 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+CandidateGrid lists the numbers every cell may hold, it is indexed [x][y][value-1] just like the options the solver
+works with. A Sukaku puzzle is given as a CandidateGrid on an empty board instead of as numbers.
+*/
+type CandidateGrid [9][9][9]bool
+
+// FullCandidateGrid allows every number in every cell
+func FullCandidateGrid() CandidateGrid {
+	c := CandidateGrid{}
+	for x := 0; x < 9; x++ {
+		for y := 0; y < 9; y++ {
+			for v := 0; v < 9; v++ {
+				c[x][y][v] = true
+			}
+		}
+	}
+	return c
+}
+
+func (c *CandidateGrid) Allows(x, y, value int) bool {
+	return c[x][y][value-1]
+}
+
+func (c *CandidateGrid) Allow(x, y, value int) {
+	c[x][y][value-1] = true
+}
+
+func (c *CandidateGrid) Remove(x, y, value int) {
+	c[x][y][value-1] = false
+}
+
+// Candidates lists the numbers allowed in the cell from smallest to largest
+func (c *CandidateGrid) Candidates(x, y int) []int {
+	var result []int
+	for v := 0; v < 9; v++ {
+		if c[x][y][v] {
+			result = append(result, v+1)
+		}
+	}
+	return result
+}
+
+/*
+String writes the candidates of every cell as a run of digits, one row per line with the cells separated by a space.
+A cell without candidates is written as 0. The result can be read back with ParseCandidateGrid.
+*/
+func (c *CandidateGrid) String() string {
+	var result strings.Builder
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			if x > 0 {
+				result.WriteString(" ")
+			}
+			candidates := c.Candidates(x, y)
+			if len(candidates) == 0 {
+				result.WriteString("0")
+			}
+			for _, v := range candidates {
+				result.WriteByte(byte('0' + v))
+			}
+		}
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+/*
+ParseCandidateGrid reads 81 whitespace separated cells in row order, each cell is the list of digits it allows. A cell
+written as 0 allows nothing. This is the layout most Sukaku puzzles are shared in and the one written by String.
+*/
+func ParseCandidateGrid(s string) (CandidateGrid, error) {
+	c := CandidateGrid{}
+	cells := strings.Fields(s)
+	if len(cells) != 81 {
+		return c, fmt.Errorf("expected 81 cells, found %d", len(cells))
+	}
+	for i, cell := range cells {
+		x, y := i%9, i/9
+		if cell == "0" {
+			continue
+		}
+		for _, r := range cell {
+			if r < '1' || r > '9' {
+				return CandidateGrid{}, fmt.Errorf("invalid candidate %q in %s", r, Cell{X: x, Y: y})
+			}
+			c.Allow(x, y, int(r-'0'))
+		}
+	}
+	return c, nil
+}
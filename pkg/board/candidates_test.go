@@ -0,0 +1,71 @@
+package board
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCandidateGrid(t *testing.T) {
+	row := "123456789 1 2 3 4 5 6 7 89\n"
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+		check   func(t *testing.T, c CandidateGrid)
+	}{
+		{
+			name:  "candidates per cell",
+			input: strings.Repeat(row, 9),
+			check: func(t *testing.T, c CandidateGrid) {
+				if got := c.Candidates(0, 4); !reflect.DeepEqual(got, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+					t.Errorf("Candidates(0, 4) = %v", got)
+				}
+				if got := c.Candidates(8, 8); !reflect.DeepEqual(got, []int{8, 9}) {
+					t.Errorf("Candidates(8, 8) = %v", got)
+				}
+				if !c.Allows(3, 0, 3) || c.Allows(3, 0, 4) {
+					t.Errorf("Allows() does not match cell r1c4")
+				}
+			},
+		},
+		{
+			name:  "no candidates",
+			input: strings.Repeat("0 ", 81),
+			check: func(t *testing.T, c CandidateGrid) {
+				if c != (CandidateGrid{}) {
+					t.Errorf("ParseCandidateGrid() allowed a candidate")
+				}
+			},
+		},
+		{name: "too few cells", input: strings.Repeat(row, 8), wantErr: true},
+		{name: "bad digit", input: strings.Repeat(row, 8) + "12a 1 2 3 4 5 6 7 8", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCandidateGrid(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCandidateGrid() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			tt.check(t, got)
+			again, err := ParseCandidateGrid(got.String())
+			if err != nil || again != got {
+				t.Errorf("ParseCandidateGrid(String()) = %v, %v, want the same grid", again, err)
+			}
+		})
+	}
+}
+
+func TestCandidateGrid_Remove(t *testing.T) {
+	c := FullCandidateGrid()
+	c.Remove(2, 3, 5)
+	if c.Allows(2, 3, 5) || !c.Allows(2, 3, 4) || !c.Allows(3, 2, 5) {
+		t.Errorf("Remove() changed the wrong candidate")
+	}
+	if got := c.Candidates(2, 3); !reflect.DeepEqual(got, []int{1, 2, 3, 4, 6, 7, 8, 9}) {
+		t.Errorf("Candidates() = %v", got)
+	}
+}
@@ -0,0 +1,221 @@
+package board
+
+import (
+	"hash/fnv"
+)
+
+// stackOrders lists the 6 orders of 3 groups, used for the stacks and for the columns within a stack
+var stackOrders = [6][3]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+
+/*
+Canonical finds the minimal lexicographic form of the board, minlex for short. Of every board that can be made from
+it with a Transform, it is the one whose FormatLine is the smallest when blank cells count as 0. Two boards are
+essentially the same puzzle if and only if they have the same canonical form. The transform turning the board into
+its canonical form is returned as well, its Inverse maps a solution of the canonical form back to the board.
+
+It returns ErrConstrainedBoard if the board has constraints.
+*/
+func Canonical(b *SudokuBoard) (*SudokuBoard, Transform, error) {
+	if len(b.constraints) > 0 {
+		return nil, Transform{}, ErrConstrainedBoard
+	}
+
+	s := &minlexSearch{}
+	for transposed := 0; transposed < 2; transposed++ {
+		for y := 0; y < 9; y++ {
+			for x := 0; x < 9; x++ {
+				if transposed == 0 {
+					s.grid[y][x] = uint8(b.GetAt(x, y))
+				} else {
+					s.grid[y][x] = uint8(b.GetAt(y, x))
+				}
+			}
+		}
+		s.transposed = transposed == 1
+		for _, stacks := range stackOrders {
+			for _, first := range stackOrders {
+				for _, second := range stackOrders {
+					for _, third := range stackOrders {
+						within := [3][3]int{first, second, third}
+						for c := 0; c < 9; c++ {
+							s.cols[c] = stacks[c/3]*3 + within[c/3][c%3]
+						}
+						s.search(0, [10]uint8{}, 1)
+					}
+				}
+			}
+		}
+	}
+
+	canonical := &SudokuBoard{}
+	for i, v := range s.best {
+		canonical.board[i/9][i%9] = int(v)
+	}
+	return canonical, s.bestTransform, nil
+}
+
+/*
+CanonicalHash returns a stable 64 bit FNV-1a hash of the FormatLine of the canonical form of the board, boards that
+are essentially the same puzzle have the same hash. It returns ErrConstrainedBoard if the board has constraints.
+*/
+func CanonicalHash(b *SudokuBoard) (uint64, error) {
+	canonical, _, err := Canonical(b)
+	if err != nil {
+		return 0, err
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(FormatLine(canonical)))
+	return h.Sum64(), nil
+}
+
+/*
+minlexSearch tries every order of the columns and for each one picks the rows one at a time. Only the rows giving the
+smallest result so far are followed, numbers are relabeled in the order they are first seen.
+*/
+type minlexSearch struct {
+	grid       [9][9]uint8
+	transposed bool
+	cols       [9]int
+
+	rows    [9]int
+	used    [9]bool
+	current [81]uint8
+
+	found         bool
+	best          [81]uint8
+	bestTransform Transform
+}
+
+func (s *minlexSearch) search(r int, labels [10]uint8, next uint8) {
+	if r == 9 {
+		if !s.found || compareCells(s.current[:], s.best[:]) < 0 {
+			s.found = true
+			s.best = s.current
+			s.bestTransform = s.transform(labels, next)
+		}
+		return
+	}
+	prefix := 0
+	if s.found {
+		prefix = compareCells(s.current[:r*9], s.best[:r*9])
+		if prefix > 0 {
+			return
+		}
+	}
+
+	// rows must stay in their band, a new band starts every 3 rows. Of rows or bands that are the same, such as the
+	// empty rows of a puzzle, only the first is tried as swapping them changes nothing.
+	var candidates [9]int
+	count := 0
+	for row := 0; row < 9; row++ {
+		band := row / 3
+		if s.used[row] || (r%3 != 0 && band != s.rows[r-1]/3) || (r%3 == 0 && s.bandUsed(band)) ||
+			s.repeatsRow(row) || (r%3 == 0 && s.repeatsBand(band)) {
+			continue
+		}
+		candidates[count] = row
+		count++
+	}
+
+	var rowCells [9][9]uint8
+	minimum := -1
+	for i := 0; i < count; i++ {
+		rowCells[i], _, _ = s.relabelRow(candidates[i], labels, next)
+		if minimum == -1 || compareCells(rowCells[i][:], rowCells[minimum][:]) < 0 {
+			minimum = i
+		}
+	}
+	if s.found && prefix == 0 && compareCells(rowCells[minimum][:], s.best[r*9:r*9+9]) > 0 {
+		return
+	}
+
+	for i := 0; i < count; i++ {
+		if rowCells[i] != rowCells[minimum] {
+			continue
+		}
+		row := candidates[i]
+		_, rowLabels, rowNext := s.relabelRow(row, labels, next)
+		copy(s.current[r*9:r*9+9], rowCells[i][:])
+		s.rows[r] = row
+		s.used[row] = true
+		s.search(r+1, rowLabels, rowNext)
+		s.used[row] = false
+	}
+}
+
+// relabelRow returns the row in the current column order with its numbers relabeled, adding labels as needed
+func (s *minlexSearch) relabelRow(row int, labels [10]uint8, next uint8) ([9]uint8, [10]uint8, uint8) {
+	var result [9]uint8
+	for c := 0; c < 9; c++ {
+		v := s.grid[row][s.cols[c]]
+		if v == 0 {
+			continue
+		}
+		if labels[v] == 0 {
+			labels[v] = next
+			next++
+		}
+		result[c] = labels[v]
+	}
+	return result, labels, next
+}
+
+// transform builds the Transform for the current rows and columns, numbers that never appear get the labels left
+func (s *minlexSearch) transform(labels [10]uint8, next uint8) Transform {
+	t := Transform{}
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			x, y := s.cols[c], s.rows[r]
+			if s.transposed {
+				x, y = y, x
+			}
+			t.cells[r*9+c] = uint8(y*9 + x)
+		}
+	}
+	for v := 1; v <= 9; v++ {
+		if labels[v] == 0 {
+			labels[v] = next
+			next++
+		}
+	}
+	t.digits = labels
+	return t
+}
+
+func (s *minlexSearch) bandUsed(band int) bool {
+	return s.used[band*3] || s.used[band*3+1] || s.used[band*3+2]
+}
+
+// repeatsRow checks for an unused row before it in the same band with the same numbers
+func (s *minlexSearch) repeatsRow(row int) bool {
+	for other := row / 3 * 3; other < row; other++ {
+		if !s.used[other] && s.grid[other] == s.grid[row] {
+			return true
+		}
+	}
+	return false
+}
+
+// repeatsBand checks for an unused band before it with the same rows
+func (s *minlexSearch) repeatsBand(band int) bool {
+	for other := 0; other < band; other++ {
+		if !s.bandUsed(other) && s.grid[other*3] == s.grid[band*3] && s.grid[other*3+1] == s.grid[band*3+1] &&
+			s.grid[other*3+2] == s.grid[band*3+2] {
+			return true
+		}
+	}
+	return false
+}
+
+// compareCells compares two runs of cells like strings.Compare
+func compareCells(a, b []uint8) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+package board
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	puzzle, err := ParseLine("53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sparse, err := ParseLine("000000010400000000020000000000050407008000300001090000300400200050100000000806000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		board *SudokuBoard
+	}{
+		{name: "solution", board: transformTestSolution},
+		{name: "puzzle", board: puzzle},
+		{name: "17 clues", board: sparse},
+		{name: "empty", board: &SudokuBoard{}},
+	}
+	rng := rand.New(rand.NewSource(36))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canonical, transform, err := Canonical(tt.board)
+			if err != nil {
+				t.Fatalf("Canonical() error = %v", err)
+			}
+			moved, _ := transform.Apply(tt.board)
+			if FormatLine(moved) != FormatLine(canonical) {
+				t.Errorf("transform gives %s, want %s", FormatLine(moved), FormatLine(canonical))
+			}
+			again, _, _ := Canonical(canonical)
+			if FormatLine(again) != FormatLine(canonical) {
+				t.Errorf("canonical form of the canonical form is %s, want %s", FormatLine(again), FormatLine(canonical))
+			}
+
+			hash, _ := CanonicalHash(tt.board)
+			for i := 0; i < 5; i++ {
+				other, _ := RandomTransform(rng).Apply(tt.board)
+				otherCanonical, _, _ := Canonical(other)
+				if FormatLine(otherCanonical) != FormatLine(canonical) {
+					t.Errorf("transformed board has canonical form %s, want %s", FormatLine(otherCanonical), FormatLine(canonical))
+				}
+				if otherHash, _ := CanonicalHash(other); otherHash != hash {
+					t.Errorf("transformed board has hash %x, want %x", otherHash, hash)
+				}
+			}
+		})
+	}
+}
+
+func TestCanonical_Solution(t *testing.T) {
+	canonical, _, _ := Canonical(transformTestSolution)
+	if line := FormatLine(canonical); line[:9] != "123456789" {
+		t.Errorf("first row of a full grid is %s, want 123456789", line[:9])
+	}
+	if !IsSolved(canonical) {
+		t.Errorf("canonical form of a solution is not solved")
+	}
+}
+
+func TestCanonicalHash_Differs(t *testing.T) {
+	// swapping two neighbouring cells is not one of the symmetries
+	other := transformTestSolution.Copy()
+	other.SetAt(0, 0, 3)
+	other.SetAt(1, 0, 5)
+	puzzle := transformTestSolution.Copy()
+	puzzle.SetAt(4, 4, 0)
+
+	hash, _ := CanonicalHash(transformTestSolution)
+	for _, b := range []*SudokuBoard{other, puzzle} {
+		if otherHash, _ := CanonicalHash(b); otherHash == hash {
+			t.Errorf("CanonicalHash(%s) = %x, same as the original", FormatLine(b), otherHash)
+		}
+	}
+}
+
+func TestCanonical_Constrained(t *testing.T) {
+	b := transformTestSolution.Copy().AddConstraints(AntiKnightConstraint{})
+	if _, _, err := Canonical(b); !errors.Is(err, ErrConstrainedBoard) {
+		t.Errorf("Canonical() error = %v, want ErrConstrainedBoard", err)
+	}
+	if _, err := CanonicalHash(b); !errors.Is(err, ErrConstrainedBoard) {
+		t.Errorf("CanonicalHash() error = %v, want ErrConstrainedBoard", err)
+	}
+}
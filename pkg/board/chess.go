@@ -0,0 +1,97 @@
+package board
+
+var knightMoves = [][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+var kingMoves = [][2]int{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}}
+var orthogonalMoves = [][2]int{{0, -1}, {-1, 0}, {1, 0}, {0, 1}}
+
+/*
+AntiKnightConstraint requires that two cells a chess knight's move apart never contain the same number.
+*/
+type AntiKnightConstraint struct{}
+
+func (AntiKnightConstraint) Verify(b *SudokuBoard) bool {
+	return verifyMoves(b, knightMoves, func(a, c int) bool { return a != c })
+}
+
+func (AntiKnightConstraint) Excludes(x, y, value int) []Candidate {
+	return excludeMoves(x, y, knightMoves, value)
+}
+
+func (AntiKnightConstraint) String() string {
+	return "antiknight"
+}
+
+/*
+AntiKingConstraint requires that two cells a chess king's move apart never contain the same number. Orthogonal
+neighbours are already covered by the row and column rules so in practice this only adds the diagonals.
+*/
+type AntiKingConstraint struct{}
+
+func (AntiKingConstraint) Verify(b *SudokuBoard) bool {
+	return verifyMoves(b, kingMoves, func(a, c int) bool { return a != c })
+}
+
+func (AntiKingConstraint) Excludes(x, y, value int) []Candidate {
+	return excludeMoves(x, y, kingMoves, value)
+}
+
+func (AntiKingConstraint) String() string {
+	return "antiking"
+}
+
+/*
+NonConsecutiveConstraint requires that orthogonally adjacent cells never contain consecutive numbers, so a 4 may not
+be placed next to a 3 or a 5.
+*/
+type NonConsecutiveConstraint struct{}
+
+func (NonConsecutiveConstraint) Verify(b *SudokuBoard) bool {
+	return verifyMoves(b, orthogonalMoves, func(a, c int) bool { return a-c != 1 && c-a != 1 })
+}
+
+func (NonConsecutiveConstraint) Excludes(x, y, value int) []Candidate {
+	return excludeMoves(x, y, orthogonalMoves, value-1, value+1)
+}
+
+func (NonConsecutiveConstraint) String() string {
+	return "nonconsecutive"
+}
+
+// verifyMoves checks every pair of filled cells that are a move apart with the allowed func
+func verifyMoves(b *SudokuBoard, moves [][2]int, allowed func(a, c int) bool) bool {
+	for x := 0; x < 9; x++ {
+		for y := 0; y < 9; y++ {
+			val := b.GetAt(x, y)
+			if val == 0 {
+				continue
+			}
+			for _, move := range moves {
+				mx, my := x+move[0], y+move[1]
+				if !inBoard(mx, my) {
+					continue
+				}
+				other := b.GetAt(mx, my)
+				if other != 0 && !allowed(val, other) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func excludeMoves(x, y int, moves [][2]int, values ...int) []Candidate {
+	var result []Candidate
+	for _, move := range moves {
+		mx, my := x+move[0], y+move[1]
+		if !inBoard(mx, my) {
+			continue
+		}
+		for _, value := range values {
+			if value >= 1 && value <= 9 {
+				result = append(result, Candidate{X: mx, Y: my, Value: value})
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,182 @@
+package board
+
+import (
+	"reflect"
+	"testing"
+)
+
+func boardWith(values ...Candidate) *SudokuBoard {
+	b := &SudokuBoard{}
+	for _, v := range values {
+		b.SetAt(v.X, v.Y, v.Value)
+	}
+	return b
+}
+
+func TestChessConstraints_Verify(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint Constraint
+		board      *SudokuBoard
+		want       bool
+	}{
+		{
+			name:       "AntiKnight empty",
+			constraint: AntiKnightConstraint{},
+			board:      &SudokuBoard{},
+			want:       true,
+		},
+		{
+			name:       "AntiKnight same value a knight apart",
+			constraint: AntiKnightConstraint{},
+			board:      boardWith(Candidate{0, 0, 5}, Candidate{1, 2, 5}),
+			want:       false,
+		},
+		{
+			name:       "AntiKnight same value a king apart",
+			constraint: AntiKnightConstraint{},
+			board:      boardWith(Candidate{0, 0, 5}, Candidate{1, 1, 5}),
+			want:       true,
+		},
+		{
+			name:       "AntiKnight different values a knight apart",
+			constraint: AntiKnightConstraint{},
+			board:      boardWith(Candidate{4, 4, 5}, Candidate{2, 3, 6}),
+			want:       true,
+		},
+		{
+			name:       "AntiKing same value diagonal",
+			constraint: AntiKingConstraint{},
+			board:      boardWith(Candidate{4, 4, 5}, Candidate{5, 3, 5}),
+			want:       false,
+		},
+		{
+			name:       "AntiKing same value a knight apart",
+			constraint: AntiKingConstraint{},
+			board:      boardWith(Candidate{0, 0, 5}, Candidate{1, 2, 5}),
+			want:       true,
+		},
+		{
+			name:       "NonConsecutive neighbours",
+			constraint: NonConsecutiveConstraint{},
+			board:      boardWith(Candidate{8, 8, 4}, Candidate{8, 7, 5}),
+			want:       false,
+		},
+		{
+			name:       "NonConsecutive diagonal",
+			constraint: NonConsecutiveConstraint{},
+			board:      boardWith(Candidate{8, 8, 4}, Candidate{7, 7, 5}),
+			want:       true,
+		},
+		{
+			name:       "NonConsecutive gap of two",
+			constraint: NonConsecutiveConstraint{},
+			board:      boardWith(Candidate{0, 0, 4}, Candidate{1, 0, 6}),
+			want:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.constraint.Verify(tt.board); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChessConstraints_Excludes(t *testing.T) {
+	type args struct {
+		x, y, value int
+	}
+	tests := []struct {
+		name       string
+		constraint Constraint
+		args       args
+		want       []Candidate
+	}{
+		{
+			name:       "AntiKnight corner",
+			constraint: AntiKnightConstraint{},
+			args:       args{x: 0, y: 0, value: 5},
+			want:       []Candidate{{1, 2, 5}, {2, 1, 5}},
+		},
+		{
+			name:       "AntiKing corner",
+			constraint: AntiKingConstraint{},
+			args:       args{x: 8, y: 8, value: 3},
+			want:       []Candidate{{7, 7, 3}, {8, 7, 3}, {7, 8, 3}},
+		},
+		{
+			name:       "NonConsecutive low value",
+			constraint: NonConsecutiveConstraint{},
+			args:       args{x: 0, y: 0, value: 1},
+			want:       []Candidate{{1, 0, 2}, {0, 1, 2}},
+		},
+		{
+			name:       "NonConsecutive high value",
+			constraint: NonConsecutiveConstraint{},
+			args:       args{x: 0, y: 0, value: 9},
+			want:       []Candidate{{1, 0, 8}, {0, 1, 8}},
+		},
+		{
+			name:       "NonConsecutive middle",
+			constraint: NonConsecutiveConstraint{},
+			args:       args{x: 4, y: 0, value: 5},
+			want:       []Candidate{{3, 0, 4}, {3, 0, 6}, {5, 0, 4}, {5, 0, 6}, {4, 1, 4}, {4, 1, 6}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.constraint.Excludes(tt.args.x, tt.args.y, tt.args.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Excludes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyBoard_Constraints(t *testing.T) {
+	solved := [9][9]int{
+		{3, 9, 8, 4, 6, 2, 5, 7, 1},
+		{4, 6, 2, 5, 7, 1, 3, 9, 8},
+		{5, 7, 1, 3, 9, 8, 4, 6, 2},
+		{9, 3, 4, 8, 2, 6, 7, 1, 5},
+		{8, 2, 6, 7, 1, 5, 9, 3, 4},
+		{7, 1, 5, 9, 3, 4, 8, 2, 6},
+		{6, 8, 3, 2, 4, 9, 1, 5, 7},
+		{2, 4, 9, 1, 5, 7, 6, 8, 3},
+		{1, 5, 7, 6, 8, 3, 2, 4, 9},
+	}
+	tests := []struct {
+		name  string
+		board *SudokuBoard
+		want  bool
+	}{
+		{
+			name:  "no constraints",
+			board: FromNumbers(solved),
+			want:  true,
+		},
+		{
+			name:  "broken constraint",
+			board: FromNumbers(solved).AddConstraints(NonConsecutiveConstraint{}),
+			want:  false,
+		},
+		{
+			name:  "multiple constraints",
+			board: FromNumbers(solved).AddConstraints(AntiKingConstraint{}, NonConsecutiveConstraint{}),
+			want:  false,
+		},
+		{
+			name:  "constraints copied",
+			board: FromNumbers(solved).AddConstraints(NonConsecutiveConstraint{}).Copy(),
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyBoard(tt.board); got != tt.want {
+				t.Errorf("VerifyBoard() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package board
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+Constraint is an extra rule placed on a SudokuBoard in addition to the classic row, column and region rules. Any
+number of constraints can be added to a board, they are all checked by VerifyBoard.
+
+  - Verify reports whether the board breaks the constraint. Like VerifyBoard it must accept incomplete boards, empty
+    cells never break a constraint.
+  - Excludes lists the candidates that can no longer be placed once value is set at (x, y). Only candidates inside
+    the board with a value in the range [1,9] are returned. Constraints that can not be expressed as eliminations
+    from a single placement may return nil, the solver falls back to Verify for those.
+  - String returns the serialized form of the constraint, ParseConstraint turns it back into a Constraint.
+*/
+type Constraint interface {
+	Verify(b *SudokuBoard) bool
+	Excludes(x, y, value int) []Candidate
+	String() string
+}
+
+// Candidate is a single value that may be placed at (X, Y).
+type Candidate struct {
+	X, Y, Value int
+}
+
+// Cell is a position on the board, it is serialized as r<row>c<column> with rows and columns counted from 1.
+type Cell struct {
+	X, Y int
+}
+
+func (c Cell) String() string {
+	return fmt.Sprintf("r%dc%d", c.Y+1, c.X+1)
+}
+
+/*
+ParseConstraint reads a constraint from its serialized form. The following forms are understood:
+
+	antiknight
+	antiking
+	nonconsecutive
+	thermo:r1c1,r1c2,r2c3      bulb first
+	arrow:r1c1;r1c2,r1c3       circle first, then the cells of the arrow
+	sandwich:row1=15           sum between the 1 and 9 in row 1, col1=15 for a column
+	kropki:white=r1c1-r1c2,black=r2c1-r3c1,negative
+	xv:x=r1c1-r1c2,v=r2c1-r3c1,negative
+	gt:r1c1>r1c2,r3c1>r2c1     the first cell of each pair is greater
+
+The trailing negative of kropki and xv is optional and marks that every dot or marker is given.
+*/
+func ParseConstraint(s string) (Constraint, error) {
+	name, args, _ := strings.Cut(s, ":")
+	switch name {
+	case "antiknight":
+		return AntiKnightConstraint{}, nil
+	case "antiking":
+		return AntiKingConstraint{}, nil
+	case "nonconsecutive":
+		return NonConsecutiveConstraint{}, nil
+	case "thermo":
+		cells, err := parseCells(args)
+		if err != nil {
+			return nil, err
+		}
+		if len(cells) < 2 {
+			return nil, fmt.Errorf("thermo needs at least 2 cells: %q", s)
+		}
+		return ThermometerConstraint{Cells: cells}, nil
+	case "arrow":
+		circle, path, found := strings.Cut(args, ";")
+		if !found {
+			return nil, fmt.Errorf("arrow is missing its circle: %q", s)
+		}
+		circleCell, err := parseCell(circle)
+		if err != nil {
+			return nil, err
+		}
+		cells, err := parseCells(path)
+		if err != nil {
+			return nil, err
+		}
+		return ArrowConstraint{Circle: circleCell, Path: cells}, nil
+	case "sandwich":
+		line, sum, found := strings.Cut(args, "=")
+		if !found {
+			return nil, fmt.Errorf("sandwich is missing its sum: %q", s)
+		}
+		c := SandwichConstraint{}
+		if strings.HasPrefix(line, "col") {
+			c.Column = true
+			line = strings.TrimPrefix(line, "col")
+		} else if strings.HasPrefix(line, "row") {
+			line = strings.TrimPrefix(line, "row")
+		} else {
+			return nil, fmt.Errorf("sandwich must be on a row or col: %q", s)
+		}
+		index, err := strconv.Atoi(line)
+		if err != nil || index < 1 || index > 9 {
+			return nil, fmt.Errorf("invalid sandwich line: %q", s)
+		}
+		c.Index = index - 1
+		c.Sum, err = strconv.Atoi(sum)
+		if err != nil || c.Sum < 0 || c.Sum > 35 {
+			return nil, fmt.Errorf("invalid sandwich sum: %q", s)
+		}
+		return c, nil
+	case "kropki":
+		white, black, negative, err := parseMarkedPairs(args, "white", "black")
+		if err != nil {
+			return nil, err
+		}
+		return KropkiConstraint{White: white, Black: black, Negative: negative}, nil
+	case "xv":
+		x, v, negative, err := parseMarkedPairs(args, "x", "v")
+		if err != nil {
+			return nil, err
+		}
+		return XVConstraint{X: x, V: v, Negative: negative}, nil
+	case "gt":
+		g := GreaterThanConstraint{}
+		for _, item := range strings.Split(args, ",") {
+			pair, err := parsePair(item, ">")
+			if err != nil {
+				return nil, err
+			}
+			g.Pairs = append(g.Pairs, pair)
+		}
+		return g, nil
+	}
+	return nil, fmt.Errorf("unknown constraint: %q", s)
+}
+
+// ParseConstraints reads a whitespace separated list of constraints as written by FormatConstraints
+func ParseConstraints(s string) ([]Constraint, error) {
+	var result []Constraint
+	for _, field := range strings.Fields(s) {
+		c, err := ParseConstraint(field)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// FormatConstraints writes the constraints as a single line that can be read back with ParseConstraints
+func FormatConstraints(constraints []Constraint) string {
+	result := make([]string, len(constraints))
+	for i, c := range constraints {
+		result[i] = c.String()
+	}
+	return strings.Join(result, " ")
+}
+
+func parseCell(s string) (Cell, error) {
+	var row, col int
+	if n, err := fmt.Sscanf(s, "r%dc%d", &row, &col); err != nil || n != 2 || fmt.Sprintf("r%dc%d", row, col) != s {
+		return Cell{}, fmt.Errorf("invalid cell: %q", s)
+	}
+	if row < 1 || row > 9 || col < 1 || col > 9 {
+		return Cell{}, fmt.Errorf("cell out of range: %q", s)
+	}
+	return Cell{X: col - 1, Y: row - 1}, nil
+}
+
+func parseCells(s string) ([]Cell, error) {
+	var cells []Cell
+	for _, part := range strings.Split(s, ",") {
+		cell, err := parseCell(part)
+		if err != nil {
+			return nil, err
+		}
+		cells = append(cells, cell)
+	}
+	return cells, nil
+}
+
+func formatCells(cells []Cell) string {
+	result := make([]string, len(cells))
+	for i, c := range cells {
+		result[i] = c.String()
+	}
+	return strings.Join(result, ",")
+}
+
+func inBoard(x, y int) bool {
+	return x >= 0 && x < 9 && y >= 0 && y < 9
+}
@@ -0,0 +1,14 @@
+package board
+
+// Diff returns the cells that hold a different number on the two boards, in reading order
+func Diff(a, b *SudokuBoard) []Cell {
+	var cells []Cell
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			if a.GetAt(x, y) != b.GetAt(x, y) {
+				cells = append(cells, Cell{X: x, Y: y})
+			}
+		}
+	}
+	return cells
+}
@@ -0,0 +1,31 @@
+package board
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	swapped := transformTestSolution.Copy()
+	swapped.SetAt(0, 0, 3)
+	swapped.SetAt(1, 0, 5)
+	puzzle := transformTestSolution.Copy()
+	puzzle.SetAt(8, 8, 0)
+
+	tests := []struct {
+		name string
+		b    *SudokuBoard
+		want []Cell
+	}{
+		{name: "same", b: transformTestSolution.Copy(), want: nil},
+		{name: "swapped", b: swapped, want: []Cell{{X: 0, Y: 0}, {X: 1, Y: 0}}},
+		{name: "blank", b: puzzle, want: []Cell{{X: 8, Y: 8}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(transformTestSolution, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CellPair is two orthogonally adjacent cells sharing a clue on the border between them
+type CellPair struct {
+	A, B Cell
+}
+
+func (p CellPair) String() string {
+	return p.A.String() + "-" + p.B.String()
+}
+
+// other returns the cell across the border from (x, y)
+func (p CellPair) other(x, y int) (Cell, bool) {
+	if p.A.X == x && p.A.Y == y {
+		return p.B, true
+	} else if p.B.X == x && p.B.Y == y {
+		return p.A, true
+	}
+	return Cell{}, false
+}
+
+func (p CellPair) joins(a, b Cell) bool {
+	return (p.A == a && p.B == b) || (p.A == b && p.B == a)
+}
+
+/*
+KropkiConstraint places dots between adjacent cells. The numbers on either side of a White dot are consecutive and
+one of the numbers next to a Black dot is double the other. When Negative is set every dot is given, so two adjacent
+cells without a dot are neither consecutive nor double.
+*/
+type KropkiConstraint struct {
+	White    []CellPair
+	Black    []CellPair
+	Negative bool
+}
+
+func (k KropkiConstraint) Verify(b *SudokuBoard) bool {
+	return verifyPairs(b, k.White, isConsecutive) &&
+		verifyPairs(b, k.Black, isDouble) &&
+		(!k.Negative || verifyUnmarked(b, func(a, c int) bool { return !isConsecutive(a, c) && !isDouble(a, c) },
+			k.White, k.Black))
+}
+
+func (k KropkiConstraint) Excludes(x, y, value int) []Candidate {
+	result := excludePairs(x, y, value, k.White, isConsecutive)
+	result = append(result, excludePairs(x, y, value, k.Black, isDouble)...)
+	if k.Negative {
+		result = append(result, excludeUnmarked(x, y, value,
+			func(a, c int) bool { return !isConsecutive(a, c) && !isDouble(a, c) }, k.White, k.Black)...)
+	}
+	return result
+}
+
+func (k KropkiConstraint) String() string {
+	return "kropki:" + formatMarkedPairs(k.Negative, "white", k.White, "black", k.Black)
+}
+
+/*
+XVConstraint places X and V markers between adjacent cells. The numbers on either side of an X add up to 10 and the
+numbers on either side of a V add up to 5. When Negative is set every marker is given, so two adjacent cells without
+a marker never add up to 5 or 10.
+*/
+type XVConstraint struct {
+	X        []CellPair
+	V        []CellPair
+	Negative bool
+}
+
+func (xv XVConstraint) Verify(b *SudokuBoard) bool {
+	return verifyPairs(b, xv.X, sumsTo(10)) &&
+		verifyPairs(b, xv.V, sumsTo(5)) &&
+		(!xv.Negative || verifyUnmarked(b, func(a, c int) bool { return a+c != 10 && a+c != 5 }, xv.X, xv.V))
+}
+
+func (xv XVConstraint) Excludes(x, y, value int) []Candidate {
+	result := excludePairs(x, y, value, xv.X, sumsTo(10))
+	result = append(result, excludePairs(x, y, value, xv.V, sumsTo(5))...)
+	if xv.Negative {
+		result = append(result, excludeUnmarked(x, y, value,
+			func(a, c int) bool { return a+c != 10 && a+c != 5 }, xv.X, xv.V)...)
+	}
+	return result
+}
+
+func (xv XVConstraint) String() string {
+	return "xv:" + formatMarkedPairs(xv.Negative, "x", xv.X, "v", xv.V)
+}
+
+/*
+GreaterThanConstraint places an arrow between adjacent cells, the number in cell A of each pair is greater than the
+number in cell B.
+*/
+type GreaterThanConstraint struct {
+	Pairs []CellPair
+}
+
+func (g GreaterThanConstraint) Verify(b *SudokuBoard) bool {
+	for _, p := range g.Pairs {
+		a, c := b.GetAt(p.A.X, p.A.Y), b.GetAt(p.B.X, p.B.Y)
+		if a != 0 && c != 0 && a <= c {
+			return false
+		}
+	}
+	return true
+}
+
+func (g GreaterThanConstraint) Excludes(x, y, value int) []Candidate {
+	var result []Candidate
+	for _, p := range g.Pairs {
+		if p.A.X == x && p.A.Y == y {
+			for v := value; v <= 9; v++ {
+				result = append(result, Candidate{X: p.B.X, Y: p.B.Y, Value: v})
+			}
+		} else if p.B.X == x && p.B.Y == y {
+			for v := 1; v <= value; v++ {
+				result = append(result, Candidate{X: p.A.X, Y: p.A.Y, Value: v})
+			}
+		}
+	}
+	return result
+}
+
+func (g GreaterThanConstraint) String() string {
+	result := make([]string, len(g.Pairs))
+	for i, p := range g.Pairs {
+		result[i] = p.A.String() + ">" + p.B.String()
+	}
+	return "gt:" + strings.Join(result, ",")
+}
+
+/*
+KropkiFromSolution places every dot that holds on a solved board, this is how a Kropki puzzle is set before removing
+numbers. Where 1 and 2 are adjacent a white dot is used.
+*/
+func KropkiFromSolution(b *SudokuBoard, negative bool) KropkiConstraint {
+	k := KropkiConstraint{Negative: negative}
+	forEachAdjacentPair(func(p CellPair) {
+		a, c := b.GetAt(p.A.X, p.A.Y), b.GetAt(p.B.X, p.B.Y)
+		if isConsecutive(a, c) {
+			k.White = append(k.White, p)
+		} else if isDouble(a, c) {
+			k.Black = append(k.Black, p)
+		}
+	})
+	return k
+}
+
+// XVFromSolution places every X and V marker that holds on a solved board
+func XVFromSolution(b *SudokuBoard, negative bool) XVConstraint {
+	xv := XVConstraint{Negative: negative}
+	forEachAdjacentPair(func(p CellPair) {
+		sum := b.GetAt(p.A.X, p.A.Y) + b.GetAt(p.B.X, p.B.Y)
+		if sum == 10 {
+			xv.X = append(xv.X, p)
+		} else if sum == 5 {
+			xv.V = append(xv.V, p)
+		}
+	})
+	return xv
+}
+
+// GreaterThanFromSolution places an arrow between every pair of adjacent cells that share a region
+func GreaterThanFromSolution(b *SudokuBoard) GreaterThanConstraint {
+	g := GreaterThanConstraint{}
+	forEachAdjacentPair(func(p CellPair) {
+		if p.A.X/3 != p.B.X/3 || p.A.Y/3 != p.B.Y/3 {
+			return
+		}
+		if b.GetAt(p.A.X, p.A.Y) > b.GetAt(p.B.X, p.B.Y) {
+			g.Pairs = append(g.Pairs, p)
+		} else {
+			g.Pairs = append(g.Pairs, CellPair{A: p.B, B: p.A})
+		}
+	})
+	return g
+}
+
+func isConsecutive(a, c int) bool {
+	return a-c == 1 || c-a == 1
+}
+
+func isDouble(a, c int) bool {
+	return a == 2*c || c == 2*a
+}
+
+func sumsTo(sum int) func(a, c int) bool {
+	return func(a, c int) bool { return a+c == sum }
+}
+
+func verifyPairs(b *SudokuBoard, pairs []CellPair, allowed func(a, c int) bool) bool {
+	for _, p := range pairs {
+		a, c := b.GetAt(p.A.X, p.A.Y), b.GetAt(p.B.X, p.B.Y)
+		if a != 0 && c != 0 && !allowed(a, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyUnmarked checks every adjacent pair without a clue in any of the marked lists
+func verifyUnmarked(b *SudokuBoard, allowed func(a, c int) bool, marked ...[]CellPair) bool {
+	valid := true
+	forEachAdjacentPair(func(p CellPair) {
+		if !valid || isMarked(p.A, p.B, marked) {
+			return
+		}
+		a, c := b.GetAt(p.A.X, p.A.Y), b.GetAt(p.B.X, p.B.Y)
+		if a != 0 && c != 0 && !allowed(a, c) {
+			valid = false
+		}
+	})
+	return valid
+}
+
+func excludePairs(x, y, value int, pairs []CellPair, allowed func(a, c int) bool) []Candidate {
+	var result []Candidate
+	for _, p := range pairs {
+		other, found := p.other(x, y)
+		if !found {
+			continue
+		}
+		for v := 1; v <= 9; v++ {
+			if !allowed(value, v) {
+				result = append(result, Candidate{X: other.X, Y: other.Y, Value: v})
+			}
+		}
+	}
+	return result
+}
+
+func excludeUnmarked(x, y, value int, allowed func(a, c int) bool, marked ...[]CellPair) []Candidate {
+	var result []Candidate
+	for _, move := range orthogonalMoves {
+		other := Cell{X: x + move[0], Y: y + move[1]}
+		if !inBoard(other.X, other.Y) || isMarked(Cell{X: x, Y: y}, other, marked) {
+			continue
+		}
+		for v := 1; v <= 9; v++ {
+			if !allowed(value, v) {
+				result = append(result, Candidate{X: other.X, Y: other.Y, Value: v})
+			}
+		}
+	}
+	return result
+}
+
+func isMarked(a, b Cell, marked [][]CellPair) bool {
+	for _, pairs := range marked {
+		for _, p := range pairs {
+			if p.joins(a, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// forEachAdjacentPair visits each pair of orthogonally adjacent cells once, left to right and top to bottom
+func forEachAdjacentPair(visit func(p CellPair)) {
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			if x < 8 {
+				visit(CellPair{A: Cell{X: x, Y: y}, B: Cell{X: x + 1, Y: y}})
+			}
+			if y < 8 {
+				visit(CellPair{A: Cell{X: x, Y: y}, B: Cell{X: x, Y: y + 1}})
+			}
+		}
+	}
+}
+
+func formatMarkedPairs(negative bool, firstName string, first []CellPair, secondName string, second []CellPair,
+) string {
+	var result []string
+	for _, p := range first {
+		result = append(result, firstName+"="+p.String())
+	}
+	for _, p := range second {
+		result = append(result, secondName+"="+p.String())
+	}
+	if negative {
+		result = append(result, "negative")
+	}
+	return strings.Join(result, ",")
+}
+
+// parseMarkedPairs reads the arguments written by formatMarkedPairs
+func parseMarkedPairs(s, firstName, secondName string) ([]CellPair, []CellPair, bool, error) {
+	var first, second []CellPair
+	negative := false
+	if s == "" {
+		return first, second, negative, nil
+	}
+	for _, item := range strings.Split(s, ",") {
+		if item == "negative" {
+			negative = true
+			continue
+		}
+		name, pairText, found := strings.Cut(item, "=")
+		if !found {
+			return nil, nil, false, fmt.Errorf("invalid marker: %q", item)
+		}
+		pair, err := parsePair(pairText, "-")
+		if err != nil {
+			return nil, nil, false, err
+		}
+		switch name {
+		case firstName:
+			first = append(first, pair)
+		case secondName:
+			second = append(second, pair)
+		default:
+			return nil, nil, false, fmt.Errorf("unknown marker: %q", item)
+		}
+	}
+	return first, second, negative, nil
+}
+
+func parsePair(s, separator string) (CellPair, error) {
+	a, c, found := strings.Cut(s, separator)
+	if !found {
+		return CellPair{}, fmt.Errorf("invalid cell pair: %q", s)
+	}
+	cellA, err := parseCell(a)
+	if err != nil {
+		return CellPair{}, err
+	}
+	cellB, err := parseCell(c)
+	if err != nil {
+		return CellPair{}, err
+	}
+	dx, dy := cellA.X-cellB.X, cellA.Y-cellB.Y
+	if dx*dx+dy*dy != 1 {
+		return CellPair{}, fmt.Errorf("cells are not adjacent: %q", s)
+	}
+	return CellPair{A: cellA, B: cellB}, nil
+}
@@ -0,0 +1,280 @@
+package board
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func pair(ax, ay, bx, by int) CellPair {
+	return CellPair{A: Cell{X: ax, Y: ay}, B: Cell{X: bx, Y: by}}
+}
+
+func TestDotConstraints_Verify(t *testing.T) {
+	kropki := KropkiConstraint{White: []CellPair{pair(0, 0, 1, 0)}, Black: []CellPair{pair(0, 0, 0, 1)}}
+	negativeKropki := KropkiConstraint{White: []CellPair{pair(0, 0, 1, 0)}, Negative: true}
+	xv := XVConstraint{X: []CellPair{pair(0, 0, 1, 0)}, V: []CellPair{pair(0, 0, 0, 1)}}
+	negativeXV := XVConstraint{V: []CellPair{pair(0, 0, 1, 0)}, Negative: true}
+	greater := GreaterThanConstraint{Pairs: []CellPair{pair(0, 0, 1, 0)}}
+	tests := []struct {
+		name       string
+		constraint Constraint
+		board      *SudokuBoard
+		want       bool
+	}{
+		{
+			name:       "kropki dots hold",
+			constraint: kropki,
+			board:      boardWith(Candidate{0, 0, 4}, Candidate{1, 0, 5}, Candidate{0, 1, 8}),
+			want:       true,
+		},
+		{
+			name:       "kropki white broken",
+			constraint: kropki,
+			board:      boardWith(Candidate{0, 0, 4}, Candidate{1, 0, 6}),
+			want:       false,
+		},
+		{
+			name:       "kropki black broken",
+			constraint: kropki,
+			board:      boardWith(Candidate{0, 0, 4}, Candidate{0, 1, 6}),
+			want:       false,
+		},
+		{
+			name:       "kropki missing dot allowed",
+			constraint: kropki,
+			board:      boardWith(Candidate{4, 4, 4}, Candidate{4, 5, 5}),
+			want:       true,
+		},
+		{
+			name:       "negative kropki missing white dot",
+			constraint: negativeKropki,
+			board:      boardWith(Candidate{4, 4, 4}, Candidate{4, 5, 5}),
+			want:       false,
+		},
+		{
+			name:       "negative kropki missing black dot",
+			constraint: negativeKropki,
+			board:      boardWith(Candidate{4, 4, 3}, Candidate{5, 4, 6}),
+			want:       false,
+		},
+		{
+			name:       "negative kropki 1 and 2 on a white dot",
+			constraint: negativeKropki,
+			board:      boardWith(Candidate{0, 0, 1}, Candidate{1, 0, 2}),
+			want:       true,
+		},
+		{
+			name:       "xv holds",
+			constraint: xv,
+			board:      boardWith(Candidate{0, 0, 3}, Candidate{1, 0, 7}, Candidate{0, 1, 2}),
+			want:       true,
+		},
+		{
+			name:       "xv broken x",
+			constraint: xv,
+			board:      boardWith(Candidate{0, 0, 3}, Candidate{1, 0, 6}),
+			want:       false,
+		},
+		{
+			name:       "negative xv missing x",
+			constraint: negativeXV,
+			board:      boardWith(Candidate{5, 5, 3}, Candidate{5, 6, 7}),
+			want:       false,
+		},
+		{
+			name:       "negative xv unmarked",
+			constraint: negativeXV,
+			board:      boardWith(Candidate{5, 5, 3}, Candidate{5, 6, 8}, Candidate{0, 0, 1}, Candidate{1, 0, 4}),
+			want:       true,
+		},
+		{
+			name:       "greater than holds",
+			constraint: greater,
+			board:      boardWith(Candidate{0, 0, 7}, Candidate{1, 0, 3}),
+			want:       true,
+		},
+		{
+			name:       "greater than broken",
+			constraint: greater,
+			board:      boardWith(Candidate{0, 0, 3}, Candidate{1, 0, 7}),
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.constraint.Verify(tt.board); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDotConstraints_Excludes(t *testing.T) {
+	type args struct {
+		x, y, value int
+	}
+	tests := []struct {
+		name       string
+		constraint Constraint
+		args       args
+		want       []Candidate
+	}{
+		{
+			name:       "white dot",
+			constraint: KropkiConstraint{White: []CellPair{pair(0, 0, 1, 0)}},
+			args:       args{x: 1, y: 0, value: 1},
+			want: []Candidate{
+				{0, 0, 1}, {0, 0, 3}, {0, 0, 4}, {0, 0, 5}, {0, 0, 6}, {0, 0, 7}, {0, 0, 8}, {0, 0, 9},
+			},
+		},
+		{
+			name:       "black dot",
+			constraint: KropkiConstraint{Black: []CellPair{pair(0, 0, 1, 0)}},
+			args:       args{x: 0, y: 0, value: 4},
+			want: []Candidate{
+				{1, 0, 1}, {1, 0, 3}, {1, 0, 4}, {1, 0, 5}, {1, 0, 6}, {1, 0, 7}, {1, 0, 9},
+			},
+		},
+		{
+			name:       "negative kropki",
+			constraint: KropkiConstraint{White: []CellPair{pair(0, 0, 1, 0)}, Negative: true},
+			args:       args{x: 0, y: 0, value: 3},
+			want: []Candidate{
+				{1, 0, 1}, {1, 0, 3}, {1, 0, 5}, {1, 0, 6}, {1, 0, 7}, {1, 0, 8}, {1, 0, 9},
+				{0, 1, 2}, {0, 1, 4}, {0, 1, 6},
+			},
+		},
+		{
+			name:       "v",
+			constraint: XVConstraint{V: []CellPair{pair(0, 0, 1, 0)}},
+			args:       args{x: 0, y: 0, value: 3},
+			want: []Candidate{
+				{1, 0, 1}, {1, 0, 3}, {1, 0, 4}, {1, 0, 5}, {1, 0, 6}, {1, 0, 7}, {1, 0, 8}, {1, 0, 9},
+			},
+		},
+		{
+			name:       "negative xv",
+			constraint: XVConstraint{X: []CellPair{pair(0, 0, 1, 0)}, Negative: true},
+			args:       args{x: 0, y: 0, value: 3},
+			want: []Candidate{
+				{1, 0, 1}, {1, 0, 2}, {1, 0, 3}, {1, 0, 4}, {1, 0, 5}, {1, 0, 6}, {1, 0, 8}, {1, 0, 9},
+				{0, 1, 2}, {0, 1, 7},
+			},
+		},
+		{
+			name:       "greater side",
+			constraint: GreaterThanConstraint{Pairs: []CellPair{pair(0, 0, 1, 0)}},
+			args:       args{x: 0, y: 0, value: 7},
+			want:       []Candidate{{1, 0, 7}, {1, 0, 8}, {1, 0, 9}},
+		},
+		{
+			name:       "smaller side",
+			constraint: GreaterThanConstraint{Pairs: []CellPair{pair(0, 0, 1, 0)}},
+			args:       args{x: 1, y: 0, value: 2},
+			want:       []Candidate{{0, 0, 1}, {0, 0, 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.constraint.Excludes(tt.args.x, tt.args.y, tt.args.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Excludes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromSolution(t *testing.T) {
+	solved := [9][9]int{}
+	for y := range solved {
+		for x := range solved[y] {
+			solved[y][x] = (y*3+y/3+x)%9 + 1
+		}
+	}
+	b := FromNumbers(solved)
+	tests := []struct {
+		name       string
+		constraint Constraint
+		marked     CellPair
+	}{
+		{
+			name:       "kropki",
+			constraint: KropkiFromSolution(b, true),
+			marked:     pair(0, 0, 1, 0),
+		},
+		{
+			name:       "xv",
+			constraint: XVFromSolution(b, true),
+			marked:     pair(1, 0, 2, 0),
+		},
+		{
+			name:       "greater than",
+			constraint: GreaterThanFromSolution(b),
+			marked:     pair(1, 0, 0, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.constraint.Verify(b) {
+				t.Errorf("Verify() = false on the solution it was made from")
+			}
+			if !strings.Contains(tt.constraint.String(), tt.marked.String()) &&
+				!strings.Contains(tt.constraint.String(), tt.marked.A.String()+">"+tt.marked.B.String()) {
+				t.Errorf("String() = %v, want it to mark %v", tt.constraint.String(), tt.marked)
+			}
+		})
+	}
+}
+
+func TestParseConstraint_Dots(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Constraint
+		wantErr bool
+	}{
+		{
+			name:  "kropki",
+			input: "kropki:white=r1c1-r1c2,black=r2c1-r3c1,negative",
+			want: KropkiConstraint{
+				White: []CellPair{pair(0, 0, 1, 0)}, Black: []CellPair{pair(0, 1, 0, 2)}, Negative: true,
+			},
+		},
+		{
+			name:  "kropki negative only",
+			input: "kropki:negative",
+			want:  KropkiConstraint{Negative: true},
+		},
+		{
+			name:  "xv",
+			input: "xv:x=r1c1-r1c2,v=r2c1-r3c1",
+			want:  XVConstraint{X: []CellPair{pair(0, 0, 1, 0)}, V: []CellPair{pair(0, 1, 0, 2)}},
+		},
+		{
+			name:  "gt",
+			input: "gt:r1c1>r1c2,r3c1>r2c1",
+			want:  GreaterThanConstraint{Pairs: []CellPair{pair(0, 0, 1, 0), pair(0, 2, 0, 1)}},
+		},
+		{name: "not adjacent", input: "kropki:white=r1c1-r1c3", wantErr: true},
+		{name: "unknown marker", input: "xv:w=r1c1-r1c2", wantErr: true},
+		{name: "missing marker", input: "xv:r1c1-r1c2", wantErr: true},
+		{name: "gt wrong separator", input: "gt:r1c1-r1c2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseConstraint(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConstraint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseConstraint() = %v, want %v", got, tt.want)
+			}
+			if got.String() != tt.input {
+				t.Errorf("String() = %v, want %v", got.String(), tt.input)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseError reports where in the text a board could not be read, Line and Column start at 1 and count characters
+type ParseError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+/*
+ParseBoard reads a board in any of the text formats below, it picks ParseLine for a single line of text and
+ParseGrid otherwise. Comment lines starting with # and section headers are skipped so SadMan files are read as well.
+*/
+func ParseBoard(s string) (*SudokuBoard, error) {
+	if trimmed := strings.TrimSpace(s); !strings.Contains(trimmed, "\n") && !strings.HasPrefix(trimmed, "#") {
+		return ParseLine(s)
+	}
+	return parseRows(s, isGridSeparator, true)
+}
+
+/*
+ParseLine reads a board written as 81 characters in row order, a blank cell is either a . or a 0. Anything after the
+81 cells must be separated by whitespace and is ignored, collections often put a rating or a comment there.
+*/
+func ParseLine(s string) (*SudokuBoard, error) {
+	s = strings.TrimRight(s, " \t\r\n")
+	b := &SudokuBoard{}
+	column := 0
+	for _, r := range s {
+		cell := column
+		column++
+		if cell == 81 {
+			if r == ' ' || r == '\t' {
+				return b, nil
+			}
+			return nil, &ParseError{Line: 1, Column: column, Msg: "expected whitespace after 81 cells"}
+		}
+		value, ok := cellValue(r)
+		if !ok {
+			return nil, &ParseError{Line: 1, Column: column, Msg: fmt.Sprintf("unexpected character %q", r)}
+		}
+		b.SetAt(cell%9, cell/9, value)
+	}
+	if column < 81 {
+		return nil, &ParseError{Line: 1, Column: column + 1, Msg: fmt.Sprintf("expected 81 cells, found %d", column)}
+	}
+	return b, nil
+}
+
+// FormatLine writes the board as a single line of 81 characters using . for blank cells
+func FormatLine(b *SudokuBoard) string {
+	var result strings.Builder
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			result.WriteByte(cellChar(b.GetAt(x, y)))
+		}
+	}
+	return result.String()
+}
+
+/*
+ParseGrid reads a board drawn as a grid with one row per line, such as the output of FormatGrid. The characters
+| - + * = and whitespace only separate cells and lines made up of them alone are skipped. A blank cell is a . a 0 or
+an _.
+*/
+func ParseGrid(s string) (*SudokuBoard, error) {
+	return parseRows(s, isGridSeparator, false)
+}
+
+/*
+FormatGrid draws the board with the regions separated by | and - so it is easy to read:
+
+	5 3 . | . 7 . | . . .
+	6 . . | 1 9 5 | . . .
+	------+-------+------
+*/
+func FormatGrid(b *SudokuBoard) string {
+	var result strings.Builder
+	for y := 0; y < 9; y++ {
+		if y == 3 || y == 6 {
+			result.WriteString("------+-------+------\n")
+		}
+		for x := 0; x < 9; x++ {
+			if x == 3 || x == 6 {
+				result.WriteString(" |")
+			}
+			if x > 0 {
+				result.WriteString(" ")
+			}
+			result.WriteByte(cellChar(b.GetAt(x, y)))
+		}
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+/*
+ParseSDK reads a SadMan Software Sudoku .sdk file. Lines starting with # hold details such as the author (#A) or the
+source (#S) and are skipped, as is the [Puzzle] header of newer files. The puzzle itself is 9 lines of 9 characters
+with . for blank cells, any section after the puzzle is ignored.
+*/
+func ParseSDK(s string) (*SudokuBoard, error) {
+	return parseRows(s, func(rune) bool { return false }, true)
+}
+
+// FormatSDK writes the board as the puzzle part of a SadMan .sdk file
+func FormatSDK(b *SudokuBoard) string {
+	var result strings.Builder
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			result.WriteByte(cellChar(b.GetAt(x, y)))
+		}
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+/*
+ParseSS reads a Simple Sudoku .ss file, a grid with | between the regions of a row and a line of dashes between the
+bands. The layout is loose enough that ParseGrid reads it.
+*/
+func ParseSS(s string) (*SudokuBoard, error) {
+	return ParseGrid(s)
+}
+
+// FormatSS writes the board in the layout of a Simple Sudoku .ss file
+func FormatSS(b *SudokuBoard) string {
+	var result strings.Builder
+	for y := 0; y < 9; y++ {
+		if y == 3 || y == 6 {
+			result.WriteString("-----------\n")
+		}
+		for x := 0; x < 9; x++ {
+			if x == 3 || x == 6 {
+				result.WriteString("|")
+			}
+			result.WriteByte(cellChar(b.GetAt(x, y)))
+		}
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+/*
+parseRows reads 9 rows of 9 cells, one row per line. Characters for which separator returns true are skipped and so
+are lines without any cells. When sections is set lines starting with # are comments and [...] lines are section
+headers, reading stops at the first section after the puzzle.
+*/
+func parseRows(s string, separator func(rune) bool, sections bool) (*SudokuBoard, error) {
+	b := &SudokuBoard{}
+	lines := strings.Split(s, "\n")
+	y := 0
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if sections {
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+			if strings.HasPrefix(trimmed, "[") {
+				if y == 0 {
+					continue
+				}
+				break
+			}
+		}
+
+		x, column := 0, 0
+		for _, r := range line {
+			column++
+			if r == ' ' || r == '\t' || separator(r) {
+				continue
+			}
+			if y == 9 {
+				return nil, &ParseError{Line: i + 1, Column: column, Msg: "more than 9 rows"}
+			}
+			value, ok := cellValue(r)
+			if !ok {
+				return nil, &ParseError{Line: i + 1, Column: column, Msg: fmt.Sprintf("unexpected character %q", r)}
+			}
+			if x == 9 {
+				return nil, &ParseError{Line: i + 1, Column: column, Msg: "more than 9 cells in a row"}
+			}
+			b.SetAt(x, y, value)
+			x++
+		}
+		if x == 0 {
+			continue
+		}
+		if x < 9 {
+			return nil, &ParseError{
+				Line: i + 1, Column: utf8.RuneCountInString(line) + 1, Msg: fmt.Sprintf("expected 9 cells, found %d", x),
+			}
+		}
+		y++
+	}
+	if y < 9 {
+		return nil, &ParseError{Line: len(lines), Column: 1, Msg: fmt.Sprintf("expected 9 rows, found %d", y)}
+	}
+	return b, nil
+}
+
+func isGridSeparator(r rune) bool {
+	return r == '|' || r == '-' || r == '+' || r == '*' || r == '='
+}
+
+func cellValue(r rune) (int, bool) {
+	switch {
+	case r >= '1' && r <= '9':
+		return int(r - '0'), true
+	case r == '.' || r == '0' || r == '_':
+		return 0, true
+	}
+	return 0, false
+}
+
+func cellChar(value int) byte {
+	if value == 0 {
+		return '.'
+	}
+	return byte('0' + value)
+}
@@ -0,0 +1,219 @@
+package board
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var formatTestBoard = FromNumbers([9][9]int{
+	{5, 3, 0, 0, 7, 0, 0, 0, 0},
+	{6, 0, 0, 1, 9, 5, 0, 0, 0},
+	{0, 9, 8, 0, 0, 0, 0, 6, 0},
+	{8, 0, 0, 0, 6, 0, 0, 0, 3},
+	{4, 0, 0, 8, 0, 3, 0, 0, 1},
+	{7, 0, 0, 0, 2, 0, 0, 0, 6},
+	{0, 6, 0, 0, 0, 0, 2, 8, 0},
+	{0, 0, 0, 4, 1, 9, 0, 0, 5},
+	{0, 0, 0, 0, 8, 0, 0, 7, 9},
+})
+
+const formatTestLine = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		parse      func(s string) (*SudokuBoard, error)
+		input      string
+		wantLine   int
+		wantColumn int
+	}{
+		{
+			name:  "line",
+			parse: ParseLine,
+			input: formatTestLine,
+		},
+		{
+			name:  "line with zeros and a comment",
+			parse: ParseLine,
+			input: strings.ReplaceAll(formatTestLine, ".", "0") + "  # rated 1.2\n",
+		},
+		{
+			name:       "line too short",
+			parse:      ParseLine,
+			input:      formatTestLine[:80],
+			wantLine:   1,
+			wantColumn: 81,
+		},
+		{
+			name:       "line bad character",
+			parse:      ParseLine,
+			input:      formatTestLine[:10] + "x" + formatTestLine[11:],
+			wantLine:   1,
+			wantColumn: 11,
+		},
+		{
+			name:       "line wide character after the cells",
+			parse:      ParseLine,
+			input:      formatTestLine + "é",
+			wantLine:   1,
+			wantColumn: 82,
+		},
+		{
+			name:       "grid wide separator",
+			parse:      ParseGrid,
+			input:      strings.Replace(FormatGrid(formatTestBoard), "6 . . | 1 9 5", "6 . . ¦ 1 9 5", 1),
+			wantLine:   2,
+			wantColumn: 7,
+		},
+		{
+			name:       "line too long",
+			parse:      ParseLine,
+			input:      formatTestLine + "1",
+			wantLine:   1,
+			wantColumn: 82,
+		},
+		{
+			name:  "grid",
+			parse: ParseGrid,
+			input: FormatGrid(formatTestBoard),
+		},
+		{
+			name:  "boxed grid",
+			parse: ParseGrid,
+			input: "+-------+-------+-------+\n" +
+				"| 5 3 _ | _ 7 _ | _ _ _ |\n" +
+				"| 6 _ _ | 1 9 5 | _ _ _ |\n" +
+				"| _ 9 8 | _ _ _ | _ 6 _ |\n" +
+				"+-------+-------+-------+\n" +
+				"| 8 _ _ | _ 6 _ | _ _ 3 |\n" +
+				"| 4 _ _ | 8 _ 3 | _ _ 1 |\n" +
+				"| 7 _ _ | _ 2 _ | _ _ 6 |\n" +
+				"+-------+-------+-------+\n" +
+				"| _ 6 _ | _ _ _ | 2 8 _ |\n" +
+				"| _ _ _ | 4 1 9 | _ _ 5 |\n" +
+				"| _ _ _ | _ 8 _ | _ 7 9 |\n" +
+				"+-------+-------+-------+\n",
+		},
+		{
+			name:       "grid short row",
+			parse:      ParseGrid,
+			input:      strings.Replace(FormatGrid(formatTestBoard), "6 . . | 1 9 5 | . . .", "6 . . | 1 9 5 | . .", 1),
+			wantLine:   2,
+			wantColumn: 20,
+		},
+		{
+			name:       "grid missing rows",
+			parse:      ParseGrid,
+			input:      "53..7....\n6..195...\n",
+			wantLine:   3,
+			wantColumn: 1,
+		},
+		{
+			name:       "grid extra row",
+			parse:      ParseGrid,
+			input:      FormatGrid(formatTestBoard) + "1 2 3 4 5 6 7 8 9\n",
+			wantLine:   12,
+			wantColumn: 1,
+		},
+		{
+			name:  "sdk",
+			parse: ParseSDK,
+			input: "#Asomeone\n#Sa newspaper\n" + FormatSDK(formatTestBoard),
+		},
+		{
+			name:  "sdk with sections",
+			parse: ParseSDK,
+			input: "[Puzzle]\n" + FormatSDK(formatTestBoard) + "[State]\n123456789\n",
+		},
+		{
+			name:       "sdk separators are not allowed",
+			parse:      ParseSDK,
+			input:      "#Asomeone\n" + FormatSS(formatTestBoard),
+			wantLine:   2,
+			wantColumn: 4,
+		},
+		{
+			name:  "ss",
+			parse: ParseSS,
+			input: FormatSS(formatTestBoard),
+		},
+		{
+			name:  "board picks line",
+			parse: ParseBoard,
+			input: formatTestLine + "\n",
+		},
+		{
+			name:  "board picks grid",
+			parse: ParseBoard,
+			input: "#Asomeone\n" + FormatGrid(formatTestBoard),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parse(tt.input)
+			if tt.wantLine == 0 {
+				if err != nil {
+					t.Fatalf("parse() error = %v", err)
+				}
+				if !reflect.DeepEqual(got, formatTestBoard) {
+					t.Errorf("parse() = \n%v, want \n%v", got, formatTestBoard)
+				}
+				return
+			}
+
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("parse() error = %v, want a ParseError", err)
+			}
+			if parseErr.Line != tt.wantLine || parseErr.Column != tt.wantColumn {
+				t.Errorf("parse() error = %v, want line %d, column %d", err, tt.wantLine, tt.wantColumn)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		format func(b *SudokuBoard) string
+		want   string
+	}{
+		{name: "line", format: FormatLine, want: formatTestLine},
+		{
+			name:   "grid",
+			format: FormatGrid,
+			want: "5 3 . | . 7 . | . . .\n" +
+				"6 . . | 1 9 5 | . . .\n" +
+				". 9 8 | . . . | . 6 .\n" +
+				"------+-------+------\n" +
+				"8 . . | . 6 . | . . 3\n" +
+				"4 . . | 8 . 3 | . . 1\n" +
+				"7 . . | . 2 . | . . 6\n" +
+				"------+-------+------\n" +
+				". 6 . | . . . | 2 8 .\n" +
+				". . . | 4 1 9 | . . 5\n" +
+				". . . | . 8 . | . 7 9\n",
+		},
+		{
+			name:   "sdk",
+			format: FormatSDK,
+			want: "53..7....\n6..195...\n.98....6.\n8...6...3\n4..8.3..1\n7...2...6\n.6....28.\n...419..5\n" +
+				"....8..79\n",
+		},
+		{
+			name:   "ss",
+			format: FormatSS,
+			want: "53.|.7.|...\n6..|195|...\n.98|...|.6.\n-----------\n8..|.6.|..3\n4..|8.3|..1\n7..|.2.|..6\n" +
+				"-----------\n.6.|...|28.\n...|419|..5\n...|.8.|.79\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.format(formatTestBoard); got != tt.want {
+				t.Errorf("format() = \n%v, want \n%v", got, tt.want)
+			}
+		})
+	}
+}
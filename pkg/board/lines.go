@@ -0,0 +1,256 @@
+package board
+
+import "fmt"
+
+/*
+ThermometerConstraint requires the numbers along the thermometer to strictly increase starting from the bulb, which
+is the first cell in Cells.
+*/
+type ThermometerConstraint struct {
+	Cells []Cell
+}
+
+func (t ThermometerConstraint) Verify(b *SudokuBoard) bool {
+	for i, cell := range t.Cells {
+		val := b.GetAt(cell.X, cell.Y)
+		if val == 0 {
+			continue
+		}
+		// there must be room for the cells before and after this one
+		if val < i+1 || val > 9-(len(t.Cells)-1-i) {
+			return false
+		}
+		for j := i + 1; j < len(t.Cells); j++ {
+			other := b.GetAt(t.Cells[j].X, t.Cells[j].Y)
+			if other != 0 && other-val < j-i {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (t ThermometerConstraint) Excludes(x, y, value int) []Candidate {
+	at := indexOfCell(t.Cells, x, y)
+	if at == -1 {
+		return nil
+	}
+
+	var result []Candidate
+	for j, cell := range t.Cells {
+		if j < at {
+			for v := value - (at - j) + 1; v <= 9; v++ {
+				if v >= 1 {
+					result = append(result, Candidate{X: cell.X, Y: cell.Y, Value: v})
+				}
+			}
+		} else if j > at {
+			for v := 1; v < value+(j-at) && v <= 9; v++ {
+				result = append(result, Candidate{X: cell.X, Y: cell.Y, Value: v})
+			}
+		}
+	}
+	return result
+}
+
+func (t ThermometerConstraint) String() string {
+	return "thermo:" + formatCells(t.Cells)
+}
+
+/*
+ArrowConstraint requires the number in the circle to equal the sum of the numbers along the arrow. Numbers may repeat
+along the arrow as long as the classic rules allow it.
+*/
+type ArrowConstraint struct {
+	Circle Cell
+	Path   []Cell
+}
+
+func (a ArrowConstraint) Verify(b *SudokuBoard) bool {
+	sum, empty := 0, 0
+	for _, cell := range a.Path {
+		val := b.GetAt(cell.X, cell.Y)
+		if val == 0 {
+			empty++
+		}
+		sum += val
+	}
+
+	circle := b.GetAt(a.Circle.X, a.Circle.Y)
+	if circle == 0 {
+		// every empty cell needs at least a 1 and the circle can hold at most a 9
+		return sum+empty <= 9
+	}
+	if empty == 0 {
+		return sum == circle
+	}
+	return sum+empty <= circle
+}
+
+func (a ArrowConstraint) Excludes(x, y, value int) []Candidate {
+	var result []Candidate
+	if x == a.Circle.X && y == a.Circle.Y {
+		// every other cell along the arrow needs at least a 1
+		maxValue := value - (len(a.Path) - 1)
+		for _, cell := range a.Path {
+			for v := 1; v <= 9; v++ {
+				if v > maxValue || (len(a.Path) == 1 && v != value) {
+					result = append(result, Candidate{X: cell.X, Y: cell.Y, Value: v})
+				}
+			}
+		}
+		return result
+	}
+
+	at := indexOfCell(a.Path, x, y)
+	if at == -1 {
+		return nil
+	}
+	minCircle := value + (len(a.Path) - 1)
+	for v := 1; v < minCircle && v <= 9; v++ {
+		result = append(result, Candidate{X: a.Circle.X, Y: a.Circle.Y, Value: v})
+	}
+	maxOther := 9 - value - (len(a.Path) - 2)
+	for j, cell := range a.Path {
+		if j == at {
+			continue
+		}
+		for v := maxOther + 1; v <= 9; v++ {
+			if v >= 1 {
+				result = append(result, Candidate{X: cell.X, Y: cell.Y, Value: v})
+			}
+		}
+	}
+	return result
+}
+
+func (a ArrowConstraint) String() string {
+	return "arrow:" + a.Circle.String() + ";" + formatCells(a.Path)
+}
+
+/*
+SandwichConstraint requires the numbers between the 1 and the 9 of a row, or a column when Column is set, to add up
+to Sum. Index is the zero based row or column the clue belongs to.
+*/
+type SandwichConstraint struct {
+	Column bool
+	Index  int
+	Sum    int
+}
+
+func (s SandwichConstraint) Verify(b *SudokuBoard) bool {
+	one, nine := -1, -1
+	for i := 0; i < 9; i++ {
+		switch s.valueAt(b, i) {
+		case 1:
+			one = i
+		case 9:
+			nine = i
+		}
+	}
+	if one == -1 || nine == -1 {
+		return true
+	}
+	if !SandwichPossible(s.Sum, abs(one-nine)-1) {
+		return false
+	}
+
+	sum, empty := 0, 0
+	for i := min(one, nine) + 1; i < max(one, nine); i++ {
+		val := s.valueAt(b, i)
+		if val == 0 {
+			empty++
+		}
+		sum += val
+	}
+	if empty == 0 {
+		return sum == s.Sum
+	}
+	return sum+2*empty <= s.Sum
+}
+
+func (s SandwichConstraint) Excludes(x, y, value int) []Candidate {
+	if value != 1 && value != 9 {
+		return nil
+	}
+	at := s.positionOf(x, y)
+	if at == -1 {
+		return nil
+	}
+
+	var result []Candidate
+	for i := 0; i < 9; i++ {
+		if i != at && !SandwichPossible(s.Sum, abs(i-at)-1) {
+			cx, cy := s.cellAt(i)
+			result = append(result, Candidate{X: cx, Y: cy, Value: 10 - value})
+		}
+	}
+	return result
+}
+
+func (s SandwichConstraint) String() string {
+	if s.Column {
+		return fmt.Sprintf("sandwich:col%d=%d", s.Index+1, s.Sum)
+	}
+	return fmt.Sprintf("sandwich:row%d=%d", s.Index+1, s.Sum)
+}
+
+// Cells lists the cells of the row or column of the sandwich in order
+func (s SandwichConstraint) Cells() []Cell {
+	cells := make([]Cell, 9)
+	for i := range cells {
+		cells[i].X, cells[i].Y = s.cellAt(i)
+	}
+	return cells
+}
+
+func (s SandwichConstraint) cellAt(i int) (int, int) {
+	if s.Column {
+		return s.Index, i
+	}
+	return i, s.Index
+}
+
+func (s SandwichConstraint) positionOf(x, y int) int {
+	if s.Column && x == s.Index {
+		return y
+	} else if !s.Column && y == s.Index {
+		return x
+	}
+	return -1
+}
+
+func (s SandwichConstraint) valueAt(b *SudokuBoard, i int) int {
+	x, y := s.cellAt(i)
+	return b.GetAt(x, y)
+}
+
+/*
+SandwichPossible reports whether count distinct numbers from the range [2,8] can add up to sum. It is used to rule out
+positions for the 1 and the 9 of a sandwich.
+*/
+func SandwichPossible(sum, count int) bool {
+	if count < 0 || count > 7 {
+		return false
+	}
+	// smallest is 2+3+...+(count+1), largest is (9-count)+...+8
+	smallest := count * (count + 3) / 2
+	largest := count * (17 - count) / 2
+	return sum >= smallest && sum <= largest
+}
+
+func indexOfCell(cells []Cell, x, y int) int {
+	for i, cell := range cells {
+		if cell.X == x && cell.Y == y {
+			return i
+		}
+	}
+	return -1
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
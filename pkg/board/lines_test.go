@@ -0,0 +1,250 @@
+package board
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLineConstraints_Verify(t *testing.T) {
+	thermo := ThermometerConstraint{Cells: []Cell{{0, 0}, {1, 0}, {2, 0}}}
+	arrow := ArrowConstraint{Circle: Cell{0, 0}, Path: []Cell{{1, 1}, {2, 2}}}
+	sandwich := SandwichConstraint{Index: 0, Sum: 5}
+	tests := []struct {
+		name       string
+		constraint Constraint
+		board      *SudokuBoard
+		want       bool
+	}{
+		{
+			name:       "thermo empty",
+			constraint: thermo,
+			board:      &SudokuBoard{},
+			want:       true,
+		},
+		{
+			name:       "thermo increasing",
+			constraint: thermo,
+			board:      boardWith(Candidate{0, 0, 2}, Candidate{1, 0, 5}, Candidate{2, 0, 9}),
+			want:       true,
+		},
+		{
+			name:       "thermo decreasing",
+			constraint: thermo,
+			board:      boardWith(Candidate{0, 0, 5}, Candidate{1, 0, 4}),
+			want:       false,
+		},
+		{
+			name:       "thermo no room for the gap",
+			constraint: thermo,
+			board:      boardWith(Candidate{0, 0, 4}, Candidate{2, 0, 5}),
+			want:       false,
+		},
+		{
+			name:       "thermo no room after the bulb",
+			constraint: thermo,
+			board:      boardWith(Candidate{0, 0, 8}),
+			want:       false,
+		},
+		{
+			name:       "arrow sums",
+			constraint: arrow,
+			board:      boardWith(Candidate{0, 0, 7}, Candidate{1, 1, 3}, Candidate{2, 2, 4}),
+			want:       true,
+		},
+		{
+			name:       "arrow wrong sum",
+			constraint: arrow,
+			board:      boardWith(Candidate{0, 0, 7}, Candidate{1, 1, 3}, Candidate{2, 2, 5}),
+			want:       false,
+		},
+		{
+			name:       "arrow partial sum too large",
+			constraint: arrow,
+			board:      boardWith(Candidate{0, 0, 7}, Candidate{1, 1, 7}),
+			want:       false,
+		},
+		{
+			name:       "arrow partial without circle",
+			constraint: arrow,
+			board:      boardWith(Candidate{1, 1, 8}),
+			want:       true,
+		},
+		{
+			name:       "arrow too large without circle",
+			constraint: arrow,
+			board:      boardWith(Candidate{1, 1, 9}),
+			want:       false,
+		},
+		{
+			name:       "sandwich sums",
+			constraint: sandwich,
+			board:      boardWith(Candidate{0, 0, 1}, Candidate{1, 0, 2}, Candidate{2, 0, 3}, Candidate{3, 0, 9}),
+			want:       true,
+		},
+		{
+			name:       "sandwich wrong sum",
+			constraint: sandwich,
+			board:      boardWith(Candidate{0, 0, 9}, Candidate{1, 0, 2}, Candidate{2, 0, 4}, Candidate{3, 0, 1}),
+			want:       false,
+		},
+		{
+			name:       "sandwich impossible gap",
+			constraint: sandwich,
+			board:      boardWith(Candidate{0, 0, 1}, Candidate{8, 0, 9}),
+			want:       false,
+		},
+		{
+			name:       "sandwich on a column",
+			constraint: SandwichConstraint{Column: true, Index: 2, Sum: 0},
+			board:      boardWith(Candidate{2, 4, 9}, Candidate{2, 5, 1}),
+			want:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.constraint.Verify(tt.board); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineConstraints_Excludes(t *testing.T) {
+	type args struct {
+		x, y, value int
+	}
+	tests := []struct {
+		name       string
+		constraint Constraint
+		args       args
+		want       []Candidate
+	}{
+		{
+			name:       "thermo bulb",
+			constraint: ThermometerConstraint{Cells: []Cell{{0, 0}, {1, 0}}},
+			args:       args{x: 0, y: 0, value: 3},
+			want:       []Candidate{{1, 0, 1}, {1, 0, 2}, {1, 0, 3}},
+		},
+		{
+			name:       "thermo tip",
+			constraint: ThermometerConstraint{Cells: []Cell{{0, 0}, {1, 0}}},
+			args:       args{x: 1, y: 0, value: 7},
+			want:       []Candidate{{0, 0, 7}, {0, 0, 8}, {0, 0, 9}},
+		},
+		{
+			name:       "thermo other cell",
+			constraint: ThermometerConstraint{Cells: []Cell{{0, 0}, {1, 0}}},
+			args:       args{x: 5, y: 5, value: 7},
+		},
+		{
+			name:       "arrow circle with one cell",
+			constraint: ArrowConstraint{Circle: Cell{0, 0}, Path: []Cell{{1, 1}}},
+			args:       args{x: 0, y: 0, value: 8},
+			want: []Candidate{
+				{1, 1, 1}, {1, 1, 2}, {1, 1, 3}, {1, 1, 4}, {1, 1, 5}, {1, 1, 6}, {1, 1, 7}, {1, 1, 9},
+			},
+		},
+		{
+			name:       "arrow circle",
+			constraint: ArrowConstraint{Circle: Cell{0, 0}, Path: []Cell{{1, 1}, {2, 2}}},
+			args:       args{x: 0, y: 0, value: 4},
+			want: []Candidate{
+				{1, 1, 4}, {1, 1, 5}, {1, 1, 6}, {1, 1, 7}, {1, 1, 8}, {1, 1, 9},
+				{2, 2, 4}, {2, 2, 5}, {2, 2, 6}, {2, 2, 7}, {2, 2, 8}, {2, 2, 9},
+			},
+		},
+		{
+			name:       "arrow path",
+			constraint: ArrowConstraint{Circle: Cell{0, 0}, Path: []Cell{{1, 1}, {2, 2}}},
+			args:       args{x: 1, y: 1, value: 6},
+			want: []Candidate{
+				{0, 0, 1}, {0, 0, 2}, {0, 0, 3}, {0, 0, 4}, {0, 0, 5}, {0, 0, 6},
+				{2, 2, 4}, {2, 2, 5}, {2, 2, 6}, {2, 2, 7}, {2, 2, 8}, {2, 2, 9},
+			},
+		},
+		{
+			name:       "sandwich one",
+			constraint: SandwichConstraint{Index: 3, Sum: 0},
+			args:       args{x: 4, y: 3, value: 1},
+			want: []Candidate{
+				{0, 3, 9}, {1, 3, 9}, {2, 3, 9}, {6, 3, 9}, {7, 3, 9}, {8, 3, 9},
+			},
+		},
+		{
+			name:       "sandwich other value",
+			constraint: SandwichConstraint{Index: 3, Sum: 0},
+			args:       args{x: 4, y: 3, value: 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.constraint.Excludes(tt.args.x, tt.args.y, tt.args.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Excludes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Constraint
+		wantErr bool
+	}{
+		{name: "antiknight", input: "antiknight", want: AntiKnightConstraint{}},
+		{name: "antiking", input: "antiking", want: AntiKingConstraint{}},
+		{name: "nonconsecutive", input: "nonconsecutive", want: NonConsecutiveConstraint{}},
+		{
+			name:  "thermo",
+			input: "thermo:r1c1,r1c2,r2c3",
+			want:  ThermometerConstraint{Cells: []Cell{{0, 0}, {1, 0}, {2, 1}}},
+		},
+		{
+			name:  "arrow",
+			input: "arrow:r5c5;r4c4,r3c3",
+			want:  ArrowConstraint{Circle: Cell{4, 4}, Path: []Cell{{3, 3}, {2, 2}}},
+		},
+		{name: "sandwich row", input: "sandwich:row1=15", want: SandwichConstraint{Index: 0, Sum: 15}},
+		{name: "sandwich col", input: "sandwich:col9=0", want: SandwichConstraint{Column: true, Index: 8}},
+		{name: "unknown", input: "killer:r1c1", wantErr: true},
+		{name: "short thermo", input: "thermo:r1c1", wantErr: true},
+		{name: "cell out of range", input: "thermo:r1c1,r1c10", wantErr: true},
+		{name: "bad cell", input: "thermo:r1c1,x", wantErr: true},
+		{name: "arrow without circle", input: "arrow:r1c1,r1c2", wantErr: true},
+		{name: "sandwich without sum", input: "sandwich:row1", wantErr: true},
+		{name: "sandwich bad line", input: "sandwich:box1=4", wantErr: true},
+		{name: "sandwich bad sum", input: "sandwich:row1=40", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseConstraint(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConstraint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseConstraint() = %v, want %v", got, tt.want)
+			}
+			if got.String() != tt.input {
+				t.Errorf("String() = %v, want %v", got.String(), tt.input)
+			}
+		})
+	}
+}
+
+func TestParseConstraints(t *testing.T) {
+	input := "antiknight thermo:r1c1,r1c2 sandwich:col3=10"
+	constraints, err := ParseConstraints(input)
+	if err != nil {
+		t.Fatalf("ParseConstraints() error = %v", err)
+	}
+	if len(constraints) != 3 {
+		t.Fatalf("ParseConstraints() = %v, want 3 constraints", constraints)
+	}
+	if got := FormatConstraints(constraints); got != input {
+		t.Errorf("FormatConstraints() = %v, want %v", got, input)
+	}
+}
@@ -0,0 +1,231 @@
+package board
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// BoardBinaryBytes is the size of the numbers of a board in its binary form, two cells to a byte
+const BoardBinaryBytes = 41
+
+// CandidateGridBinaryBytes is the size of the binary form of a CandidateGrid, one bit per candidate
+const CandidateGridBinaryBytes = 92
+
+// sudokuBoardJSON is the JSON form of a SudokuBoard, the cells are indexed [y][x]
+type sudokuBoardJSON struct {
+	Cells       [][]int  `json:"cells"`
+	Constraints []string `json:"constraints,omitempty"`
+}
+
+/*
+MarshalJSON writes the numbers of the board as 9 rows of 9 numbers under cells, so cells[y][x] on the other end is
+GetAt(x, y), and its constraints under constraints in the form of Constraint.String.
+*/
+func (s SudokuBoard) MarshalJSON() ([]byte, error) {
+	data := sudokuBoardJSON{Cells: make([][]int, 9)}
+	for y := range data.Cells {
+		data.Cells[y] = s.board[y][:]
+	}
+	for _, c := range s.constraints {
+		data.Constraints = append(data.Constraints, c.String())
+	}
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON reads the form written by MarshalJSON, replacing both the numbers and the constraints of the board
+func (s *SudokuBoard) UnmarshalJSON(data []byte) error {
+	var decoded sudokuBoardJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if len(decoded.Cells) != 9 {
+		return fmt.Errorf("expected 9 rows, found %d", len(decoded.Cells))
+	}
+	numbers := [9][9]int{}
+	for y, row := range decoded.Cells {
+		if len(row) != 9 {
+			return fmt.Errorf("expected 9 numbers in row %d, found %d", y+1, len(row))
+		}
+		for x, value := range row {
+			if value < 0 || value > 9 {
+				return fmt.Errorf("%s: number %d is outside of [0,9]", Cell{X: x, Y: y}, value)
+			}
+			numbers[y][x] = value
+		}
+	}
+	var constraints []Constraint
+	for _, text := range decoded.Constraints {
+		c, err := ParseConstraint(text)
+		if err != nil {
+			return err
+		}
+		constraints = append(constraints, c)
+	}
+	s.board = numbers
+	s.constraints = constraints
+	return nil
+}
+
+// MarshalText writes the board with FormatLine followed by its constraints, see FormatConstraints
+func (s SudokuBoard) MarshalText() ([]byte, error) {
+	text := FormatLine(&s)
+	if len(s.constraints) > 0 {
+		text += " " + FormatConstraints(s.constraints)
+	}
+	return []byte(text), nil
+}
+
+// UnmarshalText reads the form written by MarshalText, replacing both the numbers and the constraints of the board
+func (s *SudokuBoard) UnmarshalText(text []byte) error {
+	line, constraintText, _ := strings.Cut(strings.TrimSpace(string(text)), " ")
+	b, err := ParseLine(line)
+	if err != nil {
+		return err
+	}
+	constraints, err := ParseConstraints(constraintText)
+	if err != nil {
+		return err
+	}
+	s.board = b.board
+	s.constraints = constraints
+	return nil
+}
+
+/*
+MarshalBinary packs the numbers of the board two cells to a byte in row order, the first cell of each pair in the low
+4 bits. The last byte only holds the last cell. Any constraints follow as text, see FormatConstraints.
+*/
+func (s SudokuBoard) MarshalBinary() ([]byte, error) {
+	result := make([]byte, BoardBinaryBytes)
+	for i := 0; i < 81; i++ {
+		result[i/2] |= byte(s.board[i/9][i%9]) << (4 * (i % 2))
+	}
+	if len(s.constraints) > 0 {
+		result = append(result, FormatConstraints(s.constraints)...)
+	}
+	return result, nil
+}
+
+// UnmarshalBinary reads the form written by MarshalBinary, replacing both the numbers and the constraints of the board
+func (s *SudokuBoard) UnmarshalBinary(data []byte) error {
+	if len(data) < BoardBinaryBytes {
+		return fmt.Errorf("expected at least %d bytes, found %d", BoardBinaryBytes, len(data))
+	}
+	numbers := [9][9]int{}
+	for i := 0; i < 81; i++ {
+		value := int(data[i/2]>>(4*(i%2))) & 0xF
+		if value > 9 {
+			return fmt.Errorf("%s: number %d is outside of [0,9]", Cell{X: i % 9, Y: i / 9}, value)
+		}
+		numbers[i/9][i%9] = value
+	}
+	if data[BoardBinaryBytes-1]&0xF0 != 0 {
+		return fmt.Errorf("unused bits of the last cell are set")
+	}
+	constraints, err := ParseConstraints(string(data[BoardBinaryBytes:]))
+	if err != nil {
+		return err
+	}
+	s.board = numbers
+	s.constraints = constraints
+	return nil
+}
+
+// MarshalJSON writes the candidates as 9 rows of 9 lists of numbers, so grid[y][x] on the other end is Candidates(x, y)
+func (c CandidateGrid) MarshalJSON() ([]byte, error) {
+	rows := make([][][]int, 9)
+	for y := range rows {
+		rows[y] = make([][]int, 9)
+		for x := range rows[y] {
+			rows[y][x] = c.Candidates(x, y)
+			if rows[y][x] == nil {
+				rows[y][x] = []int{}
+			}
+		}
+	}
+	return json.Marshal(rows)
+}
+
+// UnmarshalJSON reads the form written by MarshalJSON
+func (c *CandidateGrid) UnmarshalJSON(data []byte) error {
+	var rows [][][]int
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return err
+	}
+	if len(rows) != 9 {
+		return fmt.Errorf("expected 9 rows, found %d", len(rows))
+	}
+	grid := CandidateGrid{}
+	for y, row := range rows {
+		if len(row) != 9 {
+			return fmt.Errorf("expected 9 cells in row %d, found %d", y+1, len(row))
+		}
+		for x, candidates := range row {
+			for _, value := range candidates {
+				if value < 1 || value > 9 {
+					return fmt.Errorf("%s: candidate %d is outside of [1,9]", Cell{X: x, Y: y}, value)
+				}
+				grid.Allow(x, y, value)
+			}
+		}
+	}
+	*c = grid
+	return nil
+}
+
+// MarshalText writes the grid in the form of String
+func (c CandidateGrid) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText reads the grid with ParseCandidateGrid
+func (c *CandidateGrid) UnmarshalText(text []byte) error {
+	grid, err := ParseCandidateGrid(string(text))
+	if err != nil {
+		return err
+	}
+	*c = grid
+	return nil
+}
+
+/*
+MarshalBinary writes the grid as a bit set where bit ((y*9)+x)*9+(value-1) is set if the cell allows the value,
+starting from the lowest bit of the first byte.
+*/
+func (c CandidateGrid) MarshalBinary() ([]byte, error) {
+	result := make([]byte, CandidateGridBinaryBytes)
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			for v := 0; v < 9; v++ {
+				if c[x][y][v] {
+					bit := (y*9+x)*9 + v
+					result[bit/8] |= 1 << (bit % 8)
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// UnmarshalBinary reads the form written by MarshalBinary
+func (c *CandidateGrid) UnmarshalBinary(data []byte) error {
+	if len(data) != CandidateGridBinaryBytes {
+		return fmt.Errorf("expected %d bytes, found %d", CandidateGridBinaryBytes, len(data))
+	}
+	// 729 bits are used, the top 7 bits of the last byte must be clear
+	if data[CandidateGridBinaryBytes-1]&0xFE != 0 {
+		return fmt.Errorf("unused bits of the last byte are set")
+	}
+	grid := CandidateGrid{}
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			for v := 0; v < 9; v++ {
+				bit := (y*9+x)*9 + v
+				grid[x][y][v] = data[bit/8]&(1<<(bit%8)) != 0
+			}
+		}
+	}
+	*c = grid
+	return nil
+}
@@ -0,0 +1,234 @@
+package board
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSudokuBoard_MarshalJSON(t *testing.T) {
+	got, err := json.Marshal(formatTestBoard)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"cells":[[5,3,0,0,7,0,0,0,0],[6,0,0,1,9,5,0,0,0],[0,9,8,0,0,0,0,6,0],[8,0,0,0,6,0,0,0,3],` +
+		`[4,0,0,8,0,3,0,0,1],[7,0,0,0,2,0,0,0,6],[0,6,0,0,0,0,2,8,0],[0,0,0,4,1,9,0,0,5],[0,0,0,0,8,0,0,7,9]]}`
+	if string(got) != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+	if value, _ := json.Marshal(*formatTestBoard); string(value) != want {
+		t.Errorf("json.Marshal() of a board value = %s, want %s", value, want)
+	}
+
+	b := &SudokuBoard{}
+	if err := json.Unmarshal(got, b); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(b, formatTestBoard) {
+		t.Errorf("json.Unmarshal() = \n%v, want \n%v", b, formatTestBoard)
+	}
+
+	variant := formatTestBoard.Copy().AddConstraints(AntiKingConstraint{},
+		ThermometerConstraint{Cells: []Cell{{X: 0, Y: 0}, {X: 1, Y: 0}}})
+	got, _ = json.Marshal(variant)
+	if !strings.HasSuffix(string(got), `"constraints":["antiking","thermo:r1c1,r1c2"]}`) {
+		t.Errorf("json.Marshal() of a variant = %s", got)
+	}
+	b = FromNumbers([9][9]int{}).AddConstraints(NonConsecutiveConstraint{})
+	if err := json.Unmarshal(got, b); err != nil || !reflect.DeepEqual(b, variant) {
+		t.Errorf("json.Unmarshal() = \n%v %v, %v, want \n%v %v", b, b.Constraints(), err, variant,
+			variant.Constraints())
+	}
+}
+
+func TestSudokuBoard_UnmarshalJSON_Invalid(t *testing.T) {
+	row := "[1,2,3,4,5,6,7,8,9]"
+	rows := strings.Repeat(row+",", 8) + row
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "not an object", input: "[" + rows + "]"},
+		{name: "too few rows", input: `{"cells":[` + strings.Repeat(row+",", 7) + row + "]}"},
+		{name: "short row", input: `{"cells":[` + strings.Repeat(row+",", 8) + "[1,2]]}"},
+		{name: "number too large", input: `{"cells":[` + strings.Repeat(row+",", 8) + "[1,2,3,4,5,6,7,8,10]]}"},
+		{name: "negative number", input: `{"cells":[[-1,2,3,4,5,6,7,8,9],` + strings.Repeat(row+",", 7) + row + "]}"},
+		{name: "unknown constraint", input: `{"cells":[` + rows + `],"constraints":["antiqueen"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := FromNumbers([9][9]int{{1}})
+			if err := json.Unmarshal([]byte(tt.input), b); err == nil {
+				t.Errorf("json.Unmarshal() error = nil, want an error")
+			}
+			if b.GetAt(0, 0) != 1 {
+				t.Errorf("json.Unmarshal() changed the board on error")
+			}
+		})
+	}
+}
+
+func TestSudokuBoard_MarshalText(t *testing.T) {
+	tests := []struct {
+		name  string
+		board *SudokuBoard
+		want  string
+	}{
+		{
+			name:  "numbers only",
+			board: formatTestBoard,
+			want:  formatTestLine,
+		},
+		{
+			name:  "with constraints",
+			board: formatTestBoard.Copy().AddConstraints(AntiKnightConstraint{}, SandwichConstraint{Index: 2, Sum: 10}),
+			want:  formatTestLine + " antiknight sandwich:row3=10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.board.MarshalText()
+			if err != nil || string(got) != tt.want {
+				t.Fatalf("MarshalText() = %s, %v, want %s", got, err, tt.want)
+			}
+			b := (&SudokuBoard{}).AddConstraints(NonConsecutiveConstraint{})
+			if err := b.UnmarshalText(got); err != nil {
+				t.Fatalf("UnmarshalText() error = %v", err)
+			}
+			if !reflect.DeepEqual(b.board, tt.board.board) ||
+				FormatConstraints(b.Constraints()) != FormatConstraints(tt.board.Constraints()) {
+				t.Errorf("UnmarshalText() = %v, want %v", b, tt.board)
+			}
+		})
+	}
+
+	if err := (&SudokuBoard{}).UnmarshalText([]byte(formatTestLine + " thermo:r1c1")); err == nil {
+		t.Errorf("UnmarshalText() error = nil for a bad constraint")
+	}
+	if err := (&SudokuBoard{}).UnmarshalText([]byte(formatTestLine[1:])); err == nil {
+		t.Errorf("UnmarshalText() error = nil for a short line")
+	}
+}
+
+func TestSudokuBoard_MarshalBinary(t *testing.T) {
+	full := FromNumbers([9][9]int{
+		{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 5, 6, 7, 8, 9},
+	})
+	fullData := []byte{
+		0x21, 0x43, 0x65, 0x87, 0x19, 0x32, 0x54, 0x76, 0x98, 0x21,
+		0x43, 0x65, 0x87, 0x19, 0x32, 0x54, 0x76, 0x98, 0x21, 0x43,
+		0x65, 0x87, 0x19, 0x32, 0x54, 0x76, 0x98, 0x21, 0x43, 0x65,
+		0x87, 0x19, 0x32, 0x54, 0x76, 0x98, 0x21, 0x43, 0x65, 0x87,
+		0x09,
+	}
+	tests := []struct {
+		name  string
+		board *SudokuBoard
+		want  []byte
+	}{
+		{
+			name:  "numbers only",
+			board: full,
+			want:  fullData,
+		},
+		{
+			name:  "with constraints",
+			board: full.Copy().AddConstraints(AntiKingConstraint{}),
+			want:  append(append([]byte{}, fullData...), "antiking"...),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.board.MarshalBinary()
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("MarshalBinary() = %v, %v, want %v", got, err, tt.want)
+			}
+			b := &SudokuBoard{}
+			if err := b.UnmarshalBinary(got); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if !reflect.DeepEqual(b, tt.board) {
+				t.Errorf("UnmarshalBinary() = %v, want %v", b, tt.board)
+			}
+		})
+	}
+
+	invalid := map[string][]byte{
+		"too short":          fullData[:40],
+		"number too large":   append([]byte{0x2A}, fullData[1:]...),
+		"unused bits set":    append(append([]byte{}, fullData[:40]...), 0x19),
+		"bad constraint":     append(append([]byte{}, fullData...), "thermo"...),
+		"unknown constraint": append(append([]byte{}, fullData...), "kings"...),
+	}
+	for name, data := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := (&SudokuBoard{}).UnmarshalBinary(data); err == nil {
+				t.Errorf("UnmarshalBinary() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestCandidateGrid_Marshal(t *testing.T) {
+	grid := CandidateGrid{}
+	grid.Allow(0, 0, 1)
+	grid.Allow(0, 0, 2)
+	grid.Allow(8, 0, 9)
+	grid.Allow(4, 8, 5)
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(grid)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		if !strings.HasPrefix(string(data), "[[[1,2],[],[],[],[],[],[],[],[9]],") {
+			t.Errorf("json.Marshal() = %s", data)
+		}
+		got := FullCandidateGrid()
+		if err := json.Unmarshal(data, &got); err != nil || got != grid {
+			t.Errorf("json.Unmarshal() = %v, %v, want %v", got.String(), err, grid.String())
+		}
+	})
+	t.Run("text", func(t *testing.T) {
+		data, _ := grid.MarshalText()
+		got := CandidateGrid{}
+		if err := got.UnmarshalText(data); err != nil || got != grid {
+			t.Errorf("UnmarshalText() = %v, %v, want %v", got.String(), err, grid.String())
+		}
+	})
+	t.Run("binary", func(t *testing.T) {
+		data, _ := grid.MarshalBinary()
+		got := CandidateGrid{}
+		if err := got.UnmarshalBinary(data); err != nil || got != grid {
+			t.Errorf("UnmarshalBinary() = %v, %v, want %v", got.String(), err, grid.String())
+		}
+	})
+
+	invalidJSON := []string{
+		`[[[1]]]`,
+		`[` + strings.Repeat(`[[],[],[],[],[],[],[],[],[]],`, 8) + `[[],[],[],[],[],[],[],[],[10]]]`,
+		`[` + strings.Repeat(`[[],[],[],[],[],[],[],[],[]],`, 8) + `[[],[],[],[],[],[],[],[0]]]`,
+	}
+	for _, input := range invalidJSON {
+		if err := json.Unmarshal([]byte(input), &CandidateGrid{}); err == nil {
+			t.Errorf("json.Unmarshal(%s) error = nil, want an error", input)
+		}
+	}
+	if err := (&CandidateGrid{}).UnmarshalBinary(make([]byte, 91)); err == nil {
+		t.Errorf("UnmarshalBinary() error = nil for a short record")
+	}
+	padded := make([]byte, CandidateGridBinaryBytes)
+	padded[CandidateGridBinaryBytes-1] = 0x02
+	if err := (&CandidateGrid{}).UnmarshalBinary(padded); err == nil {
+		t.Errorf("UnmarshalBinary() error = nil with unused bits set")
+	}
+}
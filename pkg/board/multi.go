@@ -0,0 +1,145 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SamuraiLayout places five grids so that the centre grid shares a corner box with each of the other four
+var SamuraiLayout = []Cell{{X: 0, Y: 0}, {X: 12, Y: 0}, {X: 6, Y: 6}, {X: 0, Y: 12}, {X: 12, Y: 12}}
+
+/*
+MultiBoard is a puzzle made of several 9x9 grids laid out on a larger area, such as the five grids of a Samurai
+puzzle. Grids that overlap share the numbers in the boxes they have in common. Cells are addressed with x and y on the
+whole area, the top left cell of each grid on the area is its offset.
+*/
+type MultiBoard struct {
+	grids   []*SudokuBoard
+	offsets []Cell
+	width   int
+	height  int
+}
+
+/*
+NewMultiBoard creates an empty board with one grid for each offset. Offsets must be multiples of 3 so that grids only
+ever share whole boxes. It returns an error if no offsets are given or an offset is not valid.
+*/
+func NewMultiBoard(offsets ...Cell) (*MultiBoard, error) {
+	if len(offsets) == 0 {
+		return nil, fmt.Errorf("a multi board needs at least one grid")
+	}
+	m := &MultiBoard{offsets: make([]Cell, len(offsets))}
+	for i, offset := range offsets {
+		if offset.X < 0 || offset.Y < 0 || offset.X%3 != 0 || offset.Y%3 != 0 {
+			return nil, fmt.Errorf("grid %d offset %d,%d is not a non negative multiple of 3", i, offset.X, offset.Y)
+		}
+		for j := 0; j < i; j++ {
+			if offsets[j] == offset {
+				return nil, fmt.Errorf("grid %d has the same offset as grid %d", i, j)
+			}
+		}
+		m.offsets[i] = offset
+		m.grids = append(m.grids, &SudokuBoard{})
+		m.width = max(m.width, offset.X+9)
+		m.height = max(m.height, offset.Y+9)
+	}
+	return m, nil
+}
+
+// NewSamuraiBoard creates an empty board with the SamuraiLayout
+func NewSamuraiBoard() *MultiBoard {
+	m, _ := NewMultiBoard(SamuraiLayout...)
+	return m
+}
+
+func (m *MultiBoard) Width() int {
+	return m.width
+}
+
+func (m *MultiBoard) Height() int {
+	return m.height
+}
+
+func (m *MultiBoard) GridCount() int {
+	return len(m.grids)
+}
+
+/*
+Grid returns grid i in its own coordinates, it can be used to add constraints to a single grid or to solve it on its
+own. Numbers set directly on the grid are not copied to the grids it overlaps, use SetAt for that.
+*/
+func (m *MultiBoard) Grid(i int) *SudokuBoard {
+	return m.grids[i]
+}
+
+// Offset returns the top left cell of grid i on the whole area
+func (m *MultiBoard) Offset(i int) Cell {
+	return m.offsets[i]
+}
+
+// GridsAt lists the grids covering the cell, a cell in a shared box is covered by more than one grid
+func (m *MultiBoard) GridsAt(x, y int) []int {
+	var result []int
+	for i, offset := range m.offsets {
+		if x >= offset.X && x < offset.X+9 && y >= offset.Y && y < offset.Y+9 {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// InBoard returns true if any grid covers the cell
+func (m *MultiBoard) InBoard(x, y int) bool {
+	return len(m.GridsAt(x, y)) > 0
+}
+
+// GetAt returns the number in the cell, cells no grid covers are always 0
+func (m *MultiBoard) GetAt(x, y int) int {
+	for i, offset := range m.offsets {
+		if x >= offset.X && x < offset.X+9 && y >= offset.Y && y < offset.Y+9 {
+			return m.grids[i].GetAt(x-offset.X, y-offset.Y)
+		}
+	}
+	return 0
+}
+
+// SetAt places the number in every grid covering the cell, cells no grid covers are ignored
+func (m *MultiBoard) SetAt(x, y, value int) {
+	for i, offset := range m.offsets {
+		if x >= offset.X && x < offset.X+9 && y >= offset.Y && y < offset.Y+9 {
+			m.grids[i].SetAt(x-offset.X, y-offset.Y, value)
+		}
+	}
+}
+
+// String prints the whole area, cells outside of every grid are left blank
+func (m *MultiBoard) String() string {
+	var result strings.Builder
+	for y := 0; y < m.height; y++ {
+		line := ""
+		for x := 0; x < m.width; x++ {
+			if m.InBoard(x, y) {
+				line += fmt.Sprintf("%d ", m.GetAt(x, y))
+			} else {
+				line += "  "
+			}
+		}
+		result.WriteString(strings.TrimRight(line, " "))
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+func (m *MultiBoard) Copy() *MultiBoard {
+	c := &MultiBoard{
+		grids:   make([]*SudokuBoard, len(m.grids)),
+		offsets: make([]Cell, len(m.offsets)),
+		width:   m.width,
+		height:  m.height,
+	}
+	for i, grid := range m.grids {
+		c.grids[i] = grid.Copy()
+	}
+	copy(c.offsets, m.offsets)
+	return c
+}
@@ -0,0 +1,138 @@
+package board
+
+import "testing"
+
+func TestNewMultiBoard(t *testing.T) {
+	tests := []struct {
+		name       string
+		offsets    []Cell
+		wantErr    bool
+		wantWidth  int
+		wantHeight int
+	}{
+		{name: "samurai", offsets: SamuraiLayout, wantWidth: 21, wantHeight: 21},
+		{name: "single grid", offsets: []Cell{{0, 0}}, wantWidth: 9, wantHeight: 9},
+		{name: "twodoku", offsets: []Cell{{0, 0}, {6, 6}}, wantWidth: 15, wantHeight: 15},
+		{name: "no grids", wantErr: true},
+		{name: "not on a box", offsets: []Cell{{0, 0}, {4, 6}}, wantErr: true},
+		{name: "negative", offsets: []Cell{{-3, 0}}, wantErr: true},
+		{name: "same offset", offsets: []Cell{{3, 3}, {3, 3}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMultiBoard(tt.offsets...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewMultiBoard() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Width() != tt.wantWidth || got.Height() != tt.wantHeight {
+				t.Errorf("NewMultiBoard() size = %dx%d, want %dx%d", got.Width(), got.Height(), tt.wantWidth,
+					tt.wantHeight)
+			}
+			if got.GridCount() != len(tt.offsets) {
+				t.Errorf("GridCount() = %d, want %d", got.GridCount(), len(tt.offsets))
+			}
+		})
+	}
+}
+
+func TestMultiBoard_SetAt(t *testing.T) {
+	m := NewSamuraiBoard()
+	m.SetAt(7, 7, 5)
+	m.SetAt(10, 1, 3)
+
+	if got := m.Grid(0).GetAt(7, 7); got != 5 {
+		t.Errorf("Grid(0).GetAt(7, 7) = %d, want 5", got)
+	}
+	if got := m.Grid(2).GetAt(1, 1); got != 5 {
+		t.Errorf("Grid(2).GetAt(1, 1) = %d, want 5", got)
+	}
+	if got := m.GetAt(10, 1); got != 0 {
+		t.Errorf("GetAt(10, 1) = %d, want 0 outside of every grid", got)
+	}
+	if got := m.GridsAt(7, 7); len(got) != 2 || got[0] != 0 || got[1] != 2 {
+		t.Errorf("GridsAt(7, 7) = %v, want [0 2]", got)
+	}
+
+	c := m.Copy()
+	c.SetAt(7, 7, 0)
+	if got := m.GetAt(7, 7); got != 5 {
+		t.Errorf("GetAt(7, 7) = %d after changing a copy, want 5", got)
+	}
+}
+
+func TestMultiBoard_String(t *testing.T) {
+	m, _ := NewMultiBoard(Cell{0, 0}, Cell{3, 9})
+	m.SetAt(0, 0, 1)
+	m.SetAt(11, 17, 9)
+	want := "1 0 0 0 0 0 0 0 0\n" +
+		"0 0 0 0 0 0 0 0 0\n" +
+		"0 0 0 0 0 0 0 0 0\n" +
+		"0 0 0 0 0 0 0 0 0\n" +
+		"0 0 0 0 0 0 0 0 0\n" +
+		"0 0 0 0 0 0 0 0 0\n" +
+		"0 0 0 0 0 0 0 0 0\n" +
+		"0 0 0 0 0 0 0 0 0\n" +
+		"0 0 0 0 0 0 0 0 0\n" +
+		"      0 0 0 0 0 0 0 0 0\n" +
+		"      0 0 0 0 0 0 0 0 0\n" +
+		"      0 0 0 0 0 0 0 0 0\n" +
+		"      0 0 0 0 0 0 0 0 0\n" +
+		"      0 0 0 0 0 0 0 0 0\n" +
+		"      0 0 0 0 0 0 0 0 0\n" +
+		"      0 0 0 0 0 0 0 0 0\n" +
+		"      0 0 0 0 0 0 0 0 0\n" +
+		"      0 0 0 0 0 0 0 0 9\n"
+	if got := m.String(); got != want {
+		t.Errorf("String() = \n%v, want \n%v", got, want)
+	}
+}
+
+func TestVerifyMultiBoard(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(m *MultiBoard)
+		want  bool
+	}{
+		{
+			name:  "empty",
+			setup: func(m *MultiBoard) {},
+			want:  true,
+		},
+		{
+			name: "shared cell used by both grids",
+			setup: func(m *MultiBoard) {
+				m.SetAt(6, 6, 4)
+				m.SetAt(6, 10, 5)
+				m.SetAt(8, 1, 5)
+			},
+			want: true,
+		},
+		{
+			name: "duplicate through a shared box",
+			setup: func(m *MultiBoard) {
+				m.SetAt(6, 6, 4)
+				m.SetAt(6, 12, 4)
+			},
+			want: false,
+		},
+		{
+			name: "grids disagree",
+			setup: func(m *MultiBoard) {
+				m.Grid(2).SetAt(0, 0, 4)
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewSamuraiBoard()
+			tt.setup(m)
+			if got := VerifyMultiBoard(m); got != tt.want {
+				t.Errorf("VerifyMultiBoard() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package board
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrGivenCell is returned when a player tries to change one of the numbers of the puzzle
+var ErrGivenCell = errors.New("the cell is a given")
+
+// CellState tells where the number in a cell came from
+type CellState uint8
+
+const (
+	CellEmpty CellState = iota
+	CellGiven
+	CellEntered
+)
+
+func (c CellState) String() string {
+	switch c {
+	case CellEmpty:
+		return "empty"
+	case CellGiven:
+		return "given"
+	case CellEntered:
+		return "entered"
+	}
+	return fmt.Sprintf("CellState(%d)", uint8(c))
+}
+
+/*
+PencilMarks is the set of numbers a player has noted in a cell, bit value-1 is set for each number. Numbers outside of
+[1,9] can not be marked, Has is false for them and With, Without and Toggle leave the marks as they are.
+*/
+type PencilMarks uint16
+
+const allPencilMarks PencilMarks = 0b1_1111_1111
+
+func PencilMarksOf(values ...int) PencilMarks {
+	var p PencilMarks
+	for _, v := range values {
+		p = p.With(v)
+	}
+	return p
+}
+
+// pencilMark is the bit of value, or no bit if value can not be marked
+func pencilMark(value int) PencilMarks {
+	if value < 1 || value > 9 {
+		return 0
+	}
+	return 1 << (value - 1)
+}
+
+func (p PencilMarks) Has(value int) bool {
+	return p&pencilMark(value) != 0
+}
+
+func (p PencilMarks) With(value int) PencilMarks {
+	return p | pencilMark(value)
+}
+
+func (p PencilMarks) Without(value int) PencilMarks {
+	return p &^ pencilMark(value)
+}
+
+func (p PencilMarks) Toggle(value int) PencilMarks {
+	return p ^ pencilMark(value)
+}
+
+// Values lists the marked numbers from smallest to largest
+func (p PencilMarks) Values() []int {
+	var result []int
+	for v := 1; v <= 9; v++ {
+		if p.Has(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func (p PencilMarks) String() string {
+	var result strings.Builder
+	for _, v := range p.Values() {
+		result.WriteByte(byte('0' + v))
+	}
+	return result.String()
+}
+
+// Colour is a tag a player puts on a cell to highlight it, the client decides what each tag looks like
+type Colour uint8
+
+const ColourNone Colour = 0
+
+/*
+PlayerBoard is a puzzle being played. It keeps the numbers of the puzzle, the givens, apart from the numbers the
+player entered so only the latter can be changed. Every cell can also hold pencil marks and a colour tag.
+*/
+type PlayerBoard struct {
+	puzzle  *SudokuBoard
+	entries [9][9]int
+	marks   [9][9]PencilMarks
+	colours [9][9]Colour
+}
+
+// NewPlayerBoard starts playing the puzzle, every filled in cell of the puzzle is a given
+func NewPlayerBoard(puzzle *SudokuBoard) *PlayerBoard {
+	return &PlayerBoard{puzzle: puzzle.Copy()}
+}
+
+// Puzzle returns a copy of the board with only the givens
+func (p *PlayerBoard) Puzzle() *SudokuBoard {
+	return p.puzzle.Copy()
+}
+
+// Board returns a copy of the board with both the givens and the entered numbers, it keeps the puzzle constraints
+func (p *PlayerBoard) Board() *SudokuBoard {
+	b := p.puzzle.Copy()
+	for x := 0; x < 9; x++ {
+		for y := 0; y < 9; y++ {
+			if p.entries[y][x] != 0 {
+				b.SetAt(x, y, p.entries[y][x])
+			}
+		}
+	}
+	return b
+}
+
+func (p *PlayerBoard) State(x, y int) CellState {
+	if p.puzzle.GetAt(x, y) != 0 {
+		return CellGiven
+	} else if p.entries[y][x] != 0 {
+		return CellEntered
+	}
+	return CellEmpty
+}
+
+// GetAt returns the given or entered number of the cell, 0 if it is empty
+func (p *PlayerBoard) GetAt(x, y int) int {
+	if given := p.puzzle.GetAt(x, y); given != 0 {
+		return given
+	}
+	return p.entries[y][x]
+}
+
+// Enter places a number in the cell, 0 empties it. It returns ErrGivenCell if the cell is a given.
+func (p *PlayerBoard) Enter(x, y, value int) error {
+	if err := p.checkChangeable(x, y); err != nil {
+		return err
+	}
+	if value < 0 || value > 9 {
+		return fmt.Errorf("%s: number %d is outside of [0,9]", Cell{X: x, Y: y}, value)
+	}
+	p.entries[y][x] = value
+	return nil
+}
+
+func (p *PlayerBoard) PencilMarks(x, y int) PencilMarks {
+	return p.marks[y][x]
+}
+
+// SetPencilMarks replaces the marks of the cell. It returns ErrGivenCell if the cell is a given.
+func (p *PlayerBoard) SetPencilMarks(x, y int, marks PencilMarks) error {
+	if err := p.checkChangeable(x, y); err != nil {
+		return err
+	}
+	if marks&^allPencilMarks != 0 {
+		return fmt.Errorf("%s: pencil marks %b hold numbers outside of [1,9]", Cell{X: x, Y: y}, marks)
+	}
+	p.marks[y][x] = marks
+	return nil
+}
+
+func (p *PlayerBoard) Colour(x, y int) Colour {
+	return p.colours[y][x]
+}
+
+// SetColour tags the cell, givens can be coloured as well
+func (p *PlayerBoard) SetColour(x, y int, colour Colour) {
+	p.colours[y][x] = colour
+}
+
+func (p *PlayerBoard) Copy() *PlayerBoard {
+	c := *p
+	c.puzzle = p.puzzle.Copy()
+	return &c
+}
+
+func (p *PlayerBoard) checkChangeable(x, y int) error {
+	if p.puzzle.GetAt(x, y) != 0 {
+		return fmt.Errorf("%s: %w", Cell{X: x, Y: y}, ErrGivenCell)
+	}
+	return nil
+}
+
+// playerBoardJSON is the JSON form of a PlayerBoard, all grids are indexed [y][x]
+type playerBoardJSON struct {
+	Puzzle  string           `json:"puzzle"`
+	Entries *SudokuBoard     `json:"entries"`
+	Marks   [9][9]string     `json:"marks"`
+	Colours [9][9]Colour     `json:"colours"`
+	States  *[9][9]CellState `json:"states,omitempty"`
+}
+
+/*
+MarshalJSON writes the puzzle in its text form, see SudokuBoard.MarshalText, the entered numbers as a grid and the
+pencil marks of each cell as a string of numbers. The state of every cell is included for clients, it is not read
+back.
+*/
+func (p PlayerBoard) MarshalJSON() ([]byte, error) {
+	puzzle, err := p.puzzle.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	data := playerBoardJSON{
+		Puzzle:  string(puzzle),
+		Entries: FromNumbers(p.entries),
+		Colours: p.colours,
+		States:  &[9][9]CellState{},
+	}
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			data.Marks[y][x] = p.marks[y][x].String()
+			data.States[y][x] = p.State(x, y)
+		}
+	}
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON reads the form written by MarshalJSON, entries and pencil marks on givens are rejected
+func (p *PlayerBoard) UnmarshalJSON(data []byte) error {
+	decoded := playerBoardJSON{Entries: &SudokuBoard{}}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	result := PlayerBoard{puzzle: &SudokuBoard{}, colours: decoded.Colours}
+	if err := result.puzzle.UnmarshalText([]byte(decoded.Puzzle)); err != nil {
+		return err
+	}
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			if value := decoded.Entries.GetAt(x, y); value != 0 {
+				if err := result.Enter(x, y, value); err != nil {
+					return err
+				}
+			}
+			marks, err := parsePencilMarks(decoded.Marks[y][x])
+			if err != nil {
+				return fmt.Errorf("%s: %w", Cell{X: x, Y: y}, err)
+			}
+			if marks != 0 {
+				if err := result.SetPencilMarks(x, y, marks); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	*p = result
+	return nil
+}
+
+func parsePencilMarks(s string) (PencilMarks, error) {
+	var marks PencilMarks
+	for _, r := range s {
+		if r < '1' || r > '9' {
+			return 0, fmt.Errorf("invalid pencil mark %q", r)
+		}
+		marks = marks.With(int(r - '0'))
+	}
+	return marks, nil
+}
@@ -0,0 +1,166 @@
+package board
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPencilMarks(t *testing.T) {
+	p := PencilMarksOf(1, 5, 9)
+	if !p.Has(1) || !p.Has(5) || !p.Has(9) || p.Has(2) {
+		t.Errorf("PencilMarksOf(1, 5, 9) = %v", p)
+	}
+	p = p.Without(5).With(3).Toggle(9).Toggle(2)
+	if got := p.Values(); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Values() = %v, want [1 2 3]", got)
+	}
+	if got := p.String(); got != "123" {
+		t.Errorf("String() = %v, want 123", got)
+	}
+	for _, value := range []int{-1, 0, 10, 16} {
+		if p.Has(value) || p.With(value) != p || p.Without(value) != p || p.Toggle(value) != p {
+			t.Errorf("pencil mark %d outside of [1,9] changed %v", value, p)
+		}
+	}
+}
+
+func TestPlayerBoard(t *testing.T) {
+	p := NewPlayerBoard(formatTestBoard)
+
+	tests := []struct {
+		name      string
+		change    func() error
+		wantErr   error
+		x, y      int
+		wantValue int
+		wantState CellState
+	}{
+		{
+			name:      "given",
+			change:    func() error { return nil },
+			x:         0,
+			y:         0,
+			wantValue: 5,
+			wantState: CellGiven,
+		},
+		{
+			name:      "empty",
+			change:    func() error { return nil },
+			x:         2,
+			y:         0,
+			wantState: CellEmpty,
+		},
+		{
+			name:      "enter",
+			change:    func() error { return p.Enter(2, 0, 4) },
+			x:         2,
+			y:         0,
+			wantValue: 4,
+			wantState: CellEntered,
+		},
+		{
+			name:      "clear",
+			change:    func() error { return p.Enter(2, 0, 0) },
+			x:         2,
+			y:         0,
+			wantState: CellEmpty,
+		},
+		{
+			name:      "enter on a given",
+			change:    func() error { return p.Enter(1, 0, 4) },
+			wantErr:   ErrGivenCell,
+			x:         1,
+			y:         0,
+			wantValue: 3,
+			wantState: CellGiven,
+		},
+		{
+			name:      "mark a given",
+			change:    func() error { return p.SetPencilMarks(1, 0, PencilMarksOf(4)) },
+			wantErr:   ErrGivenCell,
+			x:         1,
+			y:         0,
+			wantValue: 3,
+			wantState: CellGiven,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.change(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("change error = %v, want %v", err, tt.wantErr)
+			}
+			if got := p.GetAt(tt.x, tt.y); got != tt.wantValue {
+				t.Errorf("GetAt() = %v, want %v", got, tt.wantValue)
+			}
+			if got := p.State(tt.x, tt.y); got != tt.wantState {
+				t.Errorf("State() = %v, want %v", got, tt.wantState)
+			}
+		})
+	}
+
+	if err := p.Enter(2, 0, 10); err == nil {
+		t.Errorf("Enter(2, 0, 10) error = nil, want an error")
+	}
+	if err := p.SetPencilMarks(2, 0, PencilMarks(1<<9)); err == nil {
+		t.Errorf("SetPencilMarks() error = nil for a mark above 9")
+	}
+}
+
+func TestPlayerBoard_Board(t *testing.T) {
+	p := NewPlayerBoard(formatTestBoard.Copy().AddConstraints(AntiKingConstraint{}))
+	_ = p.Enter(2, 0, 4)
+
+	b := p.Board()
+	if b.GetAt(2, 0) != 4 || b.GetAt(0, 0) != 5 || len(b.Constraints()) != 1 {
+		t.Errorf("Board() = \n%v", b)
+	}
+	if p.Puzzle().GetAt(2, 0) != 0 {
+		t.Errorf("Puzzle() holds an entered number")
+	}
+
+	c := p.Copy()
+	_ = c.Enter(2, 0, 1)
+	if p.GetAt(2, 0) != 4 {
+		t.Errorf("changing a copy changed the board")
+	}
+}
+
+func TestPlayerBoard_MarshalJSON(t *testing.T) {
+	p := NewPlayerBoard(formatTestBoard.Copy().AddConstraints(AntiKnightConstraint{}))
+	_ = p.Enter(2, 0, 4)
+	_ = p.SetPencilMarks(3, 0, PencilMarksOf(2, 6))
+	p.SetColour(0, 0, 3)
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	got := &PlayerBoard{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("json.Unmarshal() = %+v, want %+v", got, p)
+	}
+	if byValue, _ := json.Marshal(*p); string(byValue) != string(data) {
+		t.Errorf("json.Marshal() of a value = %s, want %s", byValue, data)
+	}
+
+	invalid := map[string]string{
+		"entry on a given": `{"puzzle":"` + formatTestLine + `","entries":{"cells":[[1,0,0,0,0,0,0,0,0],[0,0,0,0,0,0,0,0,0],` +
+			`[0,0,0,0,0,0,0,0,0],[0,0,0,0,0,0,0,0,0],[0,0,0,0,0,0,0,0,0],[0,0,0,0,0,0,0,0,0],[0,0,0,0,0,0,0,0,0],` +
+			`[0,0,0,0,0,0,0,0,0],[0,0,0,0,0,0,0,0,0]]}}`,
+		"mark on a given": `{"puzzle":"` + formatTestLine + `","marks":[["1"]]}`,
+		"bad mark":        `{"puzzle":"` + formatTestLine + `","marks":[["","","x"]]}`,
+		"bad puzzle":      `{"puzzle":"123"}`,
+	}
+	for name, input := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(input), &PlayerBoard{}); err == nil {
+				t.Errorf("json.Unmarshal() error = nil, want an error")
+			}
+		})
+	}
+}
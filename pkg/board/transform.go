@@ -0,0 +1,301 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+// ErrConstrainedBoard is returned when a board with constraints is transformed, most constraints do not survive it
+var ErrConstrainedBoard = errors.New("boards with constraints can not be transformed")
+
+/*
+Transform turns a puzzle into an equivalent one by moving its cells around and relabeling its numbers. The moved
+board has as many solutions as the original and needs the same techniques to solve. Transforms are built from the
+operations below, combined with Then and reversed with Inverse, so a re-skinned puzzle can be mapped back.
+*/
+type Transform struct {
+	// cells[i] is the index (y*9)+x of the cell that ends up at index i
+	cells [81]uint8
+	// digits[v] is the number v is relabeled to, digits[0] is always 0
+	digits [10]uint8
+}
+
+// Identity leaves every board as it is
+func Identity() Transform {
+	t := Transform{}
+	for i := range t.cells {
+		t.cells[i] = uint8(i)
+	}
+	for v := range t.digits {
+		t.digits[v] = uint8(v)
+	}
+	return t
+}
+
+// moveCells builds a transform where the cell at x, y comes from the cell from returns
+func moveCells(from func(x, y int) (int, int)) Transform {
+	t := Identity()
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			fx, fy := from(x, y)
+			t.cells[y*9+x] = uint8(fy*9 + fx)
+		}
+	}
+	return t
+}
+
+// Rotate90 turns the board a quarter turn clockwise
+func Rotate90() Transform {
+	return moveCells(func(x, y int) (int, int) { return y, 8 - x })
+}
+
+func Rotate180() Transform {
+	return moveCells(func(x, y int) (int, int) { return 8 - x, 8 - y })
+}
+
+// Rotate270 turns the board a quarter turn counterclockwise
+func Rotate270() Transform {
+	return moveCells(func(x, y int) (int, int) { return 8 - y, x })
+}
+
+// MirrorHorizontal reflects the board left to right
+func MirrorHorizontal() Transform {
+	return moveCells(func(x, y int) (int, int) { return 8 - x, y })
+}
+
+// MirrorVertical reflects the board top to bottom
+func MirrorVertical() Transform {
+	return moveCells(func(x, y int) (int, int) { return x, 8 - y })
+}
+
+// Transpose reflects the board along the main diagonal, so rows become columns
+func Transpose() Transform {
+	return moveCells(func(x, y int) (int, int) { return y, x })
+}
+
+// AntiTranspose reflects the board along the diagonal from the top right to the bottom left
+func AntiTranspose() Transform {
+	return moveCells(func(x, y int) (int, int) { return 8 - y, 8 - x })
+}
+
+// SwapBands swaps two bands, the bands are the groups of 3 rows numbered 0 to 2 from the top
+func SwapBands(a, b int) (Transform, error) {
+	if !validIndex(a, 3) || !validIndex(b, 3) {
+		return Transform{}, fmt.Errorf("bands %d and %d must be in [0,2]", a, b)
+	}
+	return moveCells(func(x, y int) (int, int) { return x, swapGroup(y, a, b) }), nil
+}
+
+// SwapStacks swaps two stacks, the stacks are the groups of 3 columns numbered 0 to 2 from the left
+func SwapStacks(a, b int) (Transform, error) {
+	if !validIndex(a, 3) || !validIndex(b, 3) {
+		return Transform{}, fmt.Errorf("stacks %d and %d must be in [0,2]", a, b)
+	}
+	return moveCells(func(x, y int) (int, int) { return swapGroup(x, a, b), y }), nil
+}
+
+// SwapRows swaps two rows of the same band
+func SwapRows(a, b int) (Transform, error) {
+	if !validIndex(a, 9) || !validIndex(b, 9) || a/3 != b/3 {
+		return Transform{}, fmt.Errorf("rows %d and %d are not in the same band", a, b)
+	}
+	return moveCells(func(x, y int) (int, int) { return x, swapIndex(y, a, b) }), nil
+}
+
+// SwapColumns swaps two columns of the same stack
+func SwapColumns(a, b int) (Transform, error) {
+	if !validIndex(a, 9) || !validIndex(b, 9) || a/3 != b/3 {
+		return Transform{}, fmt.Errorf("columns %d and %d are not in the same stack", a, b)
+	}
+	return moveCells(func(x, y int) (int, int) { return swapIndex(x, a, b), y }), nil
+}
+
+// Relabel replaces every number v with digits[v-1], digits must hold each of the numbers 1 to 9 once
+func Relabel(digits [9]int) (Transform, error) {
+	t := Identity()
+	seen := [10]bool{}
+	for i, d := range digits {
+		if d < 1 || d > 9 || seen[d] {
+			return Transform{}, fmt.Errorf("relabeling %v is not a permutation of 1 to 9", digits)
+		}
+		seen[d] = true
+		t.digits[i+1] = uint8(d)
+	}
+	return t, nil
+}
+
+/*
+RandomTransform picks one of the 3,359,232 arrangements of the cells and one of the 9! relabelings, each of them
+equally likely.
+*/
+func RandomTransform(rng *rand.Rand) Transform {
+	t := Identity()
+	if rng.Intn(2) == 1 {
+		t = Transpose()
+	}
+	bands, stacks := rng.Perm(3), rng.Perm(3)
+	rows, columns := [3][]int{}, [3][]int{}
+	for i := range rows {
+		rows[i], columns[i] = rng.Perm(3), rng.Perm(3)
+	}
+	t = t.Then(moveCells(func(x, y int) (int, int) {
+		return stacks[x/3]*3 + columns[x/3][x%3], bands[y/3]*3 + rows[y/3][y%3]
+	}))
+
+	relabel := Identity()
+	for i, d := range rng.Perm(9) {
+		relabel.digits[i+1] = uint8(d + 1)
+	}
+	return t.Then(relabel)
+}
+
+// Then returns the transform doing t first and next after it
+func (t Transform) Then(next Transform) Transform {
+	result := Transform{}
+	for i := range result.cells {
+		result.cells[i] = t.cells[next.cells[i]]
+	}
+	for v := range result.digits {
+		result.digits[v] = next.digits[t.digits[v]]
+	}
+	return result
+}
+
+// Inverse returns the transform undoing t, t.Then(t.Inverse()) leaves every board as it is
+func (t Transform) Inverse() Transform {
+	result := Transform{}
+	for i, from := range t.cells {
+		result.cells[from] = uint8(i)
+	}
+	for v, to := range t.digits {
+		result.digits[to] = uint8(v)
+	}
+	return result
+}
+
+// MapCell returns where the cell at x, y ends up
+func (t Transform) MapCell(x, y int) (int, int) {
+	from := uint8(y*9 + x)
+	for i, c := range t.cells {
+		if c == from {
+			return i % 9, i / 9
+		}
+	}
+	return x, y
+}
+
+// MapValue returns the number value is relabeled to
+func (t Transform) MapValue(value int) int {
+	return int(t.digits[value])
+}
+
+// Apply returns a transformed copy of the board, it returns ErrConstrainedBoard if the board has constraints
+func (t Transform) Apply(b *SudokuBoard) (*SudokuBoard, error) {
+	if len(b.constraints) > 0 {
+		return nil, ErrConstrainedBoard
+	}
+	result := &SudokuBoard{}
+	for i, from := range t.cells {
+		result.board[i/9][i%9] = int(t.digits[b.board[from/9][from%9]])
+	}
+	return result, nil
+}
+
+// ApplyCandidates returns a transformed copy of the candidate grid
+func (t Transform) ApplyCandidates(c CandidateGrid) CandidateGrid {
+	result := CandidateGrid{}
+	for i, from := range t.cells {
+		for v := 1; v <= 9; v++ {
+			result[i%9][i/9][t.digits[v]-1] = c[from%9][from/9][v-1]
+		}
+	}
+	return result
+}
+
+func (t Transform) IsIdentity() bool {
+	return t == Identity()
+}
+
+/*
+MarshalBinary writes the source index of each of the 81 cells followed by the 9 relabeled numbers, so a transform can
+be kept next to the puzzle it was used on.
+*/
+func (t Transform) MarshalBinary() ([]byte, error) {
+	result := make([]byte, 0, 90)
+	result = append(result, t.cells[:]...)
+	return append(result, t.digits[1:]...), nil
+}
+
+// UnmarshalBinary reads the form written by MarshalBinary, it checks that the result keeps every row, column and region
+func (t *Transform) UnmarshalBinary(data []byte) error {
+	if len(data) != 90 {
+		return fmt.Errorf("expected 90 bytes, found %d", len(data))
+	}
+	result := Identity()
+	seen := [81]bool{}
+	for i, from := range data[:81] {
+		if from >= 81 || seen[from] {
+			return fmt.Errorf("cells are not a permutation of the board")
+		}
+		seen[from] = true
+		result.cells[i] = from
+	}
+	digits := [9]int{}
+	for i, d := range data[81:] {
+		digits[i] = int(d)
+	}
+	relabel, err := Relabel(digits)
+	if err != nil {
+		return err
+	}
+	result.digits = relabel.digits
+	if !result.keepsUnits() {
+		return fmt.Errorf("cells do not keep the rows, columns and regions of the board")
+	}
+	*t = result
+	return nil
+}
+
+// keepsUnits checks every row, column and region is moved onto a row, column or region
+func (t Transform) keepsUnits() bool {
+	unitOf := func(i int) [3]int { return [3]int{i / 9, 9 + i%9, 18 + (i/27)*3 + (i%9)/3} }
+	for unit := 0; unit < 27; unit++ {
+		// count how often the cells of the unit end up in each unit
+		counts := [27]int{}
+		for i, from := range t.cells {
+			for _, u := range unitOf(int(from)) {
+				if u == unit {
+					for _, to := range unitOf(i) {
+						counts[to]++
+					}
+				}
+			}
+		}
+		kept := false
+		for _, count := range counts {
+			kept = kept || count == 9
+		}
+		if !kept {
+			return false
+		}
+	}
+	return true
+}
+
+func validIndex(i, count int) bool {
+	return i >= 0 && i < count
+}
+
+func swapGroup(i, a, b int) int {
+	return swapIndex(i/3, a, b)*3 + i%3
+}
+
+func swapIndex(i, a, b int) int {
+	if i == a {
+		return b
+	} else if i == b {
+		return a
+	}
+	return i
+}
@@ -0,0 +1,179 @@
+package board
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+var transformTestSolution = FromNumbers([9][9]int{
+	{5, 3, 4, 6, 7, 8, 9, 1, 2},
+	{6, 7, 2, 1, 9, 5, 3, 4, 8},
+	{1, 9, 8, 3, 4, 2, 5, 6, 7},
+	{8, 5, 9, 7, 6, 1, 4, 2, 3},
+	{4, 2, 6, 8, 5, 3, 7, 9, 1},
+	{7, 1, 3, 9, 2, 4, 8, 5, 6},
+	{9, 6, 1, 5, 3, 7, 2, 8, 4},
+	{2, 8, 7, 4, 1, 9, 6, 3, 5},
+	{3, 4, 5, 2, 8, 6, 1, 7, 9},
+})
+
+func mustTransform(t Transform, err error) Transform {
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestTransform_Apply(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform Transform
+		// the number of the original board that should be at x, y
+		x, y        int
+		fromX, from int
+	}{
+		{name: "identity", transform: Identity(), x: 1, y: 0, fromX: 1, from: 0},
+		{name: "rotate 90", transform: Rotate90(), x: 0, y: 0, fromX: 0, from: 8},
+		{name: "rotate 180", transform: Rotate180(), x: 0, y: 0, fromX: 8, from: 8},
+		{name: "rotate 270", transform: Rotate270(), x: 0, y: 0, fromX: 8, from: 0},
+		{name: "mirror horizontal", transform: MirrorHorizontal(), x: 0, y: 1, fromX: 8, from: 1},
+		{name: "mirror vertical", transform: MirrorVertical(), x: 1, y: 0, fromX: 1, from: 8},
+		{name: "transpose", transform: Transpose(), x: 1, y: 0, fromX: 0, from: 1},
+		{name: "anti transpose", transform: AntiTranspose(), x: 1, y: 0, fromX: 8, from: 7},
+		{name: "swap bands", transform: mustTransform(SwapBands(0, 2)), x: 4, y: 1, fromX: 4, from: 7},
+		{name: "swap stacks", transform: mustTransform(SwapStacks(1, 2)), x: 4, y: 1, fromX: 7, from: 1},
+		{name: "swap rows", transform: mustTransform(SwapRows(3, 5)), x: 2, y: 3, fromX: 2, from: 5},
+		{name: "swap columns", transform: mustTransform(SwapColumns(6, 7)), x: 7, y: 2, fromX: 6, from: 2},
+		{name: "combined", transform: Rotate90().Then(mustTransform(SwapBands(0, 1))), x: 0, y: 3, fromX: 0, from: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.transform.Apply(transformTestSolution)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if got.GetAt(tt.x, tt.y) != transformTestSolution.GetAt(tt.fromX, tt.from) {
+				t.Errorf("Apply() = \n%v, want the number from %d,%d at %d,%d", got, tt.fromX, tt.from, tt.x, tt.y)
+			}
+			if mx, my := tt.transform.MapCell(tt.fromX, tt.from); mx != tt.x || my != tt.y {
+				t.Errorf("MapCell() = %d,%d, want %d,%d", mx, my, tt.x, tt.y)
+			}
+			if !IsSolved(got) {
+				t.Errorf("Apply() broke the solution \n%v", got)
+			}
+			back, _ := tt.transform.Inverse().Apply(got)
+			if FormatLine(back) != FormatLine(transformTestSolution) {
+				t.Errorf("Inverse().Apply() = \n%v, want \n%v", back, transformTestSolution)
+			}
+		})
+	}
+}
+
+func TestTransform_Then(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Transform
+		want Transform
+	}{
+		{name: "two quarter turns", got: Rotate90().Then(Rotate90()), want: Rotate180()},
+		{name: "four quarter turns", got: Rotate90().Then(Rotate90()).Then(Rotate180()), want: Identity()},
+		{name: "both mirrors", got: MirrorHorizontal().Then(MirrorVertical()), want: Rotate180()},
+		{name: "transpose and mirror", got: Transpose().Then(MirrorHorizontal()), want: Rotate90()},
+		{name: "inverse of a turn", got: Rotate90().Inverse(), want: Rotate270()},
+		{name: "transpose twice", got: Transpose().Then(Transpose()), want: Identity()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("Then() does not match")
+			}
+		})
+	}
+}
+
+func TestTransform_Relabel(t *testing.T) {
+	relabel := mustTransform(Relabel([9]int{9, 8, 7, 6, 5, 4, 3, 2, 1}))
+	got, _ := relabel.Apply(transformTestSolution)
+	if got.GetAt(0, 0) != 5 || got.GetAt(1, 0) != 7 || got.GetAt(7, 0) != 9 {
+		t.Errorf("Apply() = \n%v", got)
+	}
+	if relabel.MapValue(1) != 9 || relabel.MapValue(0) != 0 {
+		t.Errorf("MapValue() does not follow the relabeling")
+	}
+	if !relabel.Then(relabel).IsIdentity() {
+		t.Errorf("relabeling twice is not the identity")
+	}
+
+	candidates := CandidateGrid{}
+	candidates.Allow(0, 0, 1)
+	candidates.Allow(0, 0, 2)
+	moved := Rotate90().Then(relabel).ApplyCandidates(candidates)
+	if got := moved.Candidates(8, 0); len(got) != 2 || got[0] != 8 || got[1] != 9 {
+		t.Errorf("ApplyCandidates() r1c9 = %v, want [8 9]", got)
+	}
+}
+
+func TestTransform_Errors(t *testing.T) {
+	_, err := Identity().Apply(transformTestSolution.Copy().AddConstraints(AntiKnightConstraint{}))
+	if !errors.Is(err, ErrConstrainedBoard) {
+		t.Errorf("Apply() error = %v, want %v", err, ErrConstrainedBoard)
+	}
+
+	invalid := map[string]func() (Transform, error){
+		"band out of range":    func() (Transform, error) { return SwapBands(0, 3) },
+		"stack out of range":   func() (Transform, error) { return SwapStacks(-1, 0) },
+		"rows of two bands":    func() (Transform, error) { return SwapRows(2, 3) },
+		"columns of two bands": func() (Transform, error) { return SwapColumns(0, 8) },
+		"repeated digit":       func() (Transform, error) { return Relabel([9]int{1, 1, 3, 4, 5, 6, 7, 8, 9}) },
+		"digit out of range":   func() (Transform, error) { return Relabel([9]int{0, 2, 3, 4, 5, 6, 7, 8, 9}) },
+	}
+	for name, build := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := build(); err == nil {
+				t.Errorf("error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestRandomTransform(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		transform := RandomTransform(rng)
+		got, _ := transform.Apply(transformTestSolution)
+		if !IsSolved(got) {
+			t.Fatalf("RandomTransform() broke the solution \n%v", got)
+		}
+
+		data, _ := transform.MarshalBinary()
+		var decoded Transform
+		if err := decoded.UnmarshalBinary(data); err != nil || decoded != transform {
+			t.Fatalf("UnmarshalBinary() = %v, want the same transform", err)
+		}
+	}
+}
+
+func TestTransform_UnmarshalBinary(t *testing.T) {
+	valid, _ := Rotate90().MarshalBinary()
+	swapped := append([]byte{}, valid...)
+	swapped[0], swapped[1] = swapped[1], swapped[0]
+	repeated := append([]byte{}, valid...)
+	repeated[0] = repeated[1]
+	badDigit := append([]byte{}, valid...)
+	badDigit[89] = 1
+
+	invalid := map[string][]byte{
+		"too short":           valid[:89],
+		"not a symmetry":      swapped,
+		"not a permutation":   repeated,
+		"digits not permuted": badDigit,
+	}
+	for name, data := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := (&Transform{}).UnmarshalBinary(data); err == nil {
+				t.Errorf("UnmarshalBinary() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -17,10 +17,12 @@ func IsSolved(board *SudokuBoard) bool {
 
 /*
 VerifyBoard checks if a SudokuBoard is valid. It checks if the board is valid by checking if each column, row, and
-region is valid. It returns true if the board is valid and false otherwise. It uses the following helper functions:
+region is valid as well as any constraints added to the board. It returns true if the board is valid and false
+otherwise. It uses the following helper functions:
   - VerifyColumn
   - VerifyRow
   - VerifyRegion
+  - VerifyConstraints
 */
 func VerifyBoard(board *SudokuBoard) bool {
 	for i := 0; i < 9; i++ {
@@ -34,6 +36,19 @@ func VerifyBoard(board *SudokuBoard) bool {
 			return false
 		}
 	}
+	return VerifyConstraints(board)
+}
+
+/*
+VerifyConstraints checks every Constraint added to the board. It returns true if none of the constraints are broken
+and false otherwise. A board without constraints is always valid.
+*/
+func VerifyConstraints(board *SudokuBoard) bool {
+	for _, c := range board.constraints {
+		if !c.Verify(board) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -131,3 +146,34 @@ var sudokuRegionMap = map[int]sudokuRegionCord{
 	7: {3, 6, 5, 8},
 	8: {6, 6, 8, 8},
 }
+
+/*
+VerifyMultiBoard checks every grid of the board with VerifyBoard and that grids sharing a cell agree on its number. It
+returns true if the board is valid and false otherwise.
+*/
+func VerifyMultiBoard(board *MultiBoard) bool {
+	for i, grid := range board.grids {
+		if !VerifyBoard(grid) {
+			return false
+		}
+		offset := board.offsets[i]
+		for x := 0; x < 9; x++ {
+			for y := 0; y < 9; y++ {
+				if grid.GetAt(x, y) != board.GetAt(offset.X+x, offset.Y+y) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// IsMultiBoardSolved returns true if every grid of the board is filled in and VerifyMultiBoard returns true
+func IsMultiBoardSolved(board *MultiBoard) bool {
+	for _, grid := range board.grids {
+		if !IsSolved(grid) {
+			return false
+		}
+	}
+	return VerifyMultiBoard(board)
+}
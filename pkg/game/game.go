@@ -0,0 +1,241 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"droidkfx.com/sudoku/pkg/board"
+)
+
+/*
+Game is a puzzle being played together with every move made so far. Moves can be undone and redone and the board can
+be rebuilt as it was after any number of moves, so a session can be replayed exactly. Making a new move after undoing
+drops the undone moves.
+*/
+type Game struct {
+	start    *board.PlayerBoard
+	current  *board.PlayerBoard
+	history  []Move
+	position int
+}
+
+// New starts a game of the puzzle, every filled in cell of the puzzle is a given
+func New(puzzle *board.SudokuBoard) *Game {
+	return FromPlayerBoard(board.NewPlayerBoard(puzzle))
+}
+
+// FromPlayerBoard starts a game from a board that may already have entries, undo never goes back further than it
+func FromPlayerBoard(p *board.PlayerBoard) *Game {
+	return &Game{start: p.Copy(), current: p.Copy()}
+}
+
+// Board returns a copy of the board as it is now
+func (g *Game) Board() *board.PlayerBoard {
+	return g.current.Copy()
+}
+
+// History returns the moves made so far, including the undone moves that can still be redone
+func (g *Game) History() []Move {
+	result := make([]Move, len(g.history))
+	copy(result, g.history)
+	return result
+}
+
+// Position is the number of moves of the history that are currently applied
+func (g *Game) Position() int {
+	return g.position
+}
+
+func (g *Game) Place(x, y, value int) error {
+	return g.Apply(Move{Kind: MovePlace, X: x, Y: y, Value: value})
+}
+
+func (g *Game) Erase(x, y int) error {
+	return g.Apply(Move{Kind: MoveErase, X: x, Y: y})
+}
+
+func (g *Game) AddMark(x, y, value int) error {
+	return g.Apply(Move{Kind: MoveAddMark, X: x, Y: y, Value: value})
+}
+
+func (g *Game) RemoveMark(x, y, value int) error {
+	return g.Apply(Move{Kind: MoveRemoveMark, X: x, Y: y, Value: value})
+}
+
+func (g *Game) SetColour(x, y int, colour board.Colour) error {
+	return g.Apply(Move{Kind: MoveColour, X: x, Y: y, Value: int(colour)})
+}
+
+/*
+Apply makes the moves in order and records them. Previous and the erased Value are filled in from the board, so moves
+from elsewhere such as the steps of the solver can be applied as they are. Moves that change nothing are not
+recorded. If a move can not be made the moves before it stay applied and the error is returned.
+*/
+func (g *Game) Apply(moves ...Move) error {
+	for _, move := range moves {
+		applied, changed, err := apply(g.current, move)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		g.history = append(g.history[:g.position], applied)
+		g.position++
+	}
+	return nil
+}
+
+// Undo reverses the last applied move, it returns false if there is nothing to undo
+func (g *Game) Undo() bool {
+	if g.position == 0 {
+		return false
+	}
+	g.position--
+	g.mustApply(g.history[g.position].Inverse())
+	return true
+}
+
+// Redo makes the last undone move again, it returns false if there is nothing to redo
+func (g *Game) Redo() bool {
+	if g.position == len(g.history) {
+		return false
+	}
+	g.mustApply(g.history[g.position])
+	g.position++
+	return true
+}
+
+// Seek undoes or redoes moves until n moves of the history are applied
+func (g *Game) Seek(n int) error {
+	if n < 0 || n > len(g.history) {
+		return fmt.Errorf("position %d is outside of the history [0,%d]", n, len(g.history))
+	}
+	for g.position > n {
+		g.Undo()
+	}
+	for g.position < n {
+		g.Redo()
+	}
+	return nil
+}
+
+// ReplayTo returns the board as it was after the first n moves of the history without changing the game
+func (g *Game) ReplayTo(n int) (*board.PlayerBoard, error) {
+	if n < 0 || n > len(g.history) {
+		return nil, fmt.Errorf("position %d is outside of the history [0,%d]", n, len(g.history))
+	}
+	return Replay(g.start, g.history[:n])
+}
+
+// Replay makes the moves on a copy of the board and returns it
+func Replay(start *board.PlayerBoard, moves []Move) (*board.PlayerBoard, error) {
+	p := start.Copy()
+	for i, move := range moves {
+		if _, _, err := apply(p, move); err != nil {
+			return nil, fmt.Errorf("move %d: %w", i, err)
+		}
+	}
+	return p, nil
+}
+
+// the moves of the history were checked when they were first made so reversing or repeating them can not fail
+func (g *Game) mustApply(move Move) {
+	if _, _, err := apply(g.current, move); err != nil {
+		panic(err)
+	}
+}
+
+// apply makes the move on the board and returns it with Previous filled in, changed is false if it was a no-op
+func apply(p *board.PlayerBoard, move Move) (Move, bool, error) {
+	if move.X < 0 || move.X > 8 || move.Y < 0 || move.Y > 8 {
+		return move, false, fmt.Errorf("cell %d,%d is outside of the board", move.X, move.Y)
+	}
+	x, y := move.X, move.Y
+	switch move.Kind {
+	case MovePlace:
+		if move.Value < 1 || move.Value > 9 {
+			return move, false, fmt.Errorf("%v: number is outside of [1,9]", move)
+		}
+		move.Previous = p.GetAt(x, y)
+		if p.State(x, y) != board.CellGiven && move.Previous == move.Value {
+			return move, false, nil
+		}
+		return move, true, p.Enter(x, y, move.Value)
+	case MoveErase:
+		move.Value = p.GetAt(x, y)
+		if p.State(x, y) == board.CellEmpty {
+			return move, false, nil
+		}
+		return move, true, p.Enter(x, y, 0)
+	case MoveAddMark, MoveRemoveMark:
+		if move.Value < 1 || move.Value > 9 {
+			return move, false, fmt.Errorf("%v: pencil mark is outside of [1,9]", move)
+		}
+		marks := p.PencilMarks(x, y)
+		if move.Kind == MoveAddMark {
+			marks = marks.With(move.Value)
+		} else {
+			marks = marks.Without(move.Value)
+		}
+		if p.State(x, y) != board.CellGiven && marks == p.PencilMarks(x, y) {
+			return move, false, nil
+		}
+		return move, true, p.SetPencilMarks(x, y, marks)
+	case MoveColour:
+		if move.Value < 0 || move.Value > 255 {
+			return move, false, fmt.Errorf("%v: colour is outside of [0,255]", move)
+		}
+		move.Previous = int(p.Colour(x, y))
+		if move.Previous == move.Value {
+			return move, false, nil
+		}
+		p.SetColour(x, y, board.Colour(move.Value))
+		return move, true, nil
+	}
+	return move, false, fmt.Errorf("unknown move kind %d", uint8(move.Kind))
+}
+
+// gameJSON is the JSON form of a Game
+type gameJSON struct {
+	Start    *board.PlayerBoard `json:"start"`
+	History  []Move             `json:"history"`
+	Position int                `json:"position"`
+}
+
+// MarshalJSON writes the board the game started from, the history and the position in it
+func (g *Game) MarshalJSON() ([]byte, error) {
+	return json.Marshal(gameJSON{Start: g.start, History: g.history, Position: g.position})
+}
+
+// UnmarshalJSON reads the form written by MarshalJSON, the whole history is replayed to check it
+func (g *Game) UnmarshalJSON(data []byte) error {
+	decoded := gameJSON{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.Start == nil {
+		return fmt.Errorf("the board the game started from is missing")
+	}
+	if decoded.Position < 0 || decoded.Position > len(decoded.History) {
+		return fmt.Errorf("position %d is outside of the history [0,%d]", decoded.Position, len(decoded.History))
+	}
+
+	result := FromPlayerBoard(decoded.Start)
+	for i, move := range decoded.History {
+		applied, changed, err := apply(result.current, move)
+		if err != nil {
+			return fmt.Errorf("move %d: %w", i, err)
+		}
+		if !changed || applied != move {
+			return fmt.Errorf("move %d: %v does not match the board", i, move)
+		}
+		result.history = append(result.history, move)
+		result.position++
+	}
+	if err := result.Seek(decoded.Position); err != nil {
+		return err
+	}
+	*g = *result
+	return nil
+}
@@ -0,0 +1,186 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/board"
+)
+
+var testPuzzle = board.FromNumbers([9][9]int{
+	{5, 3, 0, 0, 7, 0, 0, 0, 0},
+	{6, 0, 0, 1, 9, 5, 0, 0, 0},
+	{0, 9, 8, 0, 0, 0, 0, 6, 0},
+	{8, 0, 0, 0, 6, 0, 0, 0, 3},
+	{4, 0, 0, 8, 0, 3, 0, 0, 1},
+	{7, 0, 0, 0, 2, 0, 0, 0, 6},
+	{0, 6, 0, 0, 0, 0, 2, 8, 0},
+	{0, 0, 0, 4, 1, 9, 0, 0, 5},
+	{0, 0, 0, 0, 8, 0, 0, 7, 9},
+})
+
+// playSession makes a few moves of every kind and returns the game with the board after each of them
+func playSession(t *testing.T) (*Game, []*board.PlayerBoard) {
+	g := New(testPuzzle)
+	boards := []*board.PlayerBoard{g.Board()}
+	moves := []func() error{
+		func() error { return g.Place(2, 0, 4) },
+		func() error { return g.AddMark(3, 0, 2) },
+		func() error { return g.AddMark(3, 0, 6) },
+		func() error { return g.Place(2, 0, 1) },
+		func() error { return g.RemoveMark(3, 0, 2) },
+		func() error { return g.SetColour(0, 0, 2) },
+		func() error { return g.Erase(2, 0) },
+	}
+	for i, move := range moves {
+		if err := move(); err != nil {
+			t.Fatalf("move %d error = %v", i, err)
+		}
+		boards = append(boards, g.Board())
+	}
+	return g, boards
+}
+
+func TestGame_UndoRedo(t *testing.T) {
+	g, boards := playSession(t)
+	if g.Position() != len(boards)-1 {
+		t.Fatalf("Position() = %d, want %d", g.Position(), len(boards)-1)
+	}
+
+	for i := len(boards) - 2; i >= 0; i-- {
+		if !g.Undo() {
+			t.Fatalf("Undo() = false at position %d", i+1)
+		}
+		if !reflect.DeepEqual(g.Board(), boards[i]) {
+			t.Errorf("Undo() to %d = %+v, want %+v", i, g.Board(), boards[i])
+		}
+	}
+	if g.Undo() {
+		t.Errorf("Undo() = true with nothing to undo")
+	}
+
+	for i := 1; i < len(boards); i++ {
+		if !g.Redo() {
+			t.Fatalf("Redo() = false at position %d", i-1)
+		}
+		if !reflect.DeepEqual(g.Board(), boards[i]) {
+			t.Errorf("Redo() to %d = %+v, want %+v", i, g.Board(), boards[i])
+		}
+	}
+	if g.Redo() {
+		t.Errorf("Redo() = true with nothing to redo")
+	}
+}
+
+func TestGame_NewMoveDropsRedo(t *testing.T) {
+	g, _ := playSession(t)
+	g.Undo()
+	g.Undo()
+	if err := g.Place(2, 1, 7); err != nil {
+		t.Fatalf("Place() error = %v", err)
+	}
+	if g.Redo() {
+		t.Errorf("Redo() = true after a new move")
+	}
+	if got := len(g.History()); got != 6 {
+		t.Errorf("len(History()) = %d, want 6", got)
+	}
+}
+
+func TestGame_ReplayTo(t *testing.T) {
+	g, boards := playSession(t)
+	for i, want := range boards {
+		got, err := g.ReplayTo(i)
+		if err != nil {
+			t.Fatalf("ReplayTo(%d) error = %v", i, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ReplayTo(%d) = %+v, want %+v", i, got, want)
+		}
+	}
+	if _, err := g.ReplayTo(len(boards)); err == nil {
+		t.Errorf("ReplayTo() error = nil beyond the history")
+	}
+
+	if err := g.Seek(3); err != nil || !reflect.DeepEqual(g.Board(), boards[3]) {
+		t.Errorf("Seek(3) = %v, board %+v, want %+v", err, g.Board(), boards[3])
+	}
+}
+
+func TestGame_Apply(t *testing.T) {
+	tests := []struct {
+		name        string
+		move        Move
+		wantErr     error
+		wantHistory int
+	}{
+		{name: "place", move: Move{Kind: MovePlace, X: 2, Y: 0, Value: 4}, wantHistory: 1},
+		{name: "place on a given", move: Move{Kind: MovePlace, X: 0, Y: 0, Value: 4}, wantErr: board.ErrGivenCell},
+		{name: "erase a given", move: Move{Kind: MoveErase, X: 0, Y: 0}, wantErr: board.ErrGivenCell},
+		{name: "mark a given", move: Move{Kind: MoveAddMark, X: 0, Y: 0, Value: 1}, wantErr: board.ErrGivenCell},
+		{name: "erase an empty cell", move: Move{Kind: MoveErase, X: 2, Y: 0}},
+		{name: "remove a missing mark", move: Move{Kind: MoveRemoveMark, X: 2, Y: 0, Value: 1}},
+		{name: "colour a given", move: Move{Kind: MoveColour, X: 0, Y: 0, Value: 1}, wantHistory: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(testPuzzle)
+			if err := g.Apply(tt.move); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Apply() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := len(g.History()); got != tt.wantHistory {
+				t.Errorf("len(History()) = %d, want %d", got, tt.wantHistory)
+			}
+		})
+	}
+
+	invalid := []Move{
+		{Kind: MovePlace, X: 9, Y: 0, Value: 1},
+		{Kind: MovePlace, X: 2, Y: 0, Value: 10},
+		{Kind: MoveAddMark, X: 2, Y: 0},
+		{Kind: MoveColour, X: 2, Y: 0, Value: 256},
+		{Kind: MoveKind(9), X: 2, Y: 0},
+	}
+	for _, move := range invalid {
+		if err := New(testPuzzle).Apply(move); err == nil {
+			t.Errorf("Apply(%v) error = nil, want an error", move)
+		}
+	}
+}
+
+func TestGame_JSON(t *testing.T) {
+	g, _ := playSession(t)
+	g.Undo()
+
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	got := &Game{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, g) {
+		t.Errorf("json.Unmarshal() = %+v, want %+v", got, g)
+	}
+
+	var decoded map[string]json.RawMessage
+	_ = json.Unmarshal(data, &decoded)
+	invalid := map[string]string{
+		"missing start":    `{"history":[],"position":0}`,
+		"position too far": `{"start":` + string(decoded["start"]) + `,"history":[],"position":1}`,
+		"move on a given": `{"start":` + string(decoded["start"]) +
+			`,"history":[{"kind":"place","x":0,"y":0,"value":1}]}`,
+		"previous is wrong": `{"start":` + string(decoded["start"]) +
+			`,"history":[{"kind":"place","x":2,"y":0,"value":1,"previous":4}]}`,
+	}
+	for name, input := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(input), &Game{}); err == nil {
+				t.Errorf("json.Unmarshal() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package game
+
+import "fmt"
+
+// MoveKind is the kind of change a Move makes to a cell
+type MoveKind uint8
+
+const (
+	MovePlace MoveKind = iota
+	MoveErase
+	MoveAddMark
+	MoveRemoveMark
+	MoveColour
+)
+
+var moveKindNames = map[MoveKind]string{
+	MovePlace:      "place",
+	MoveErase:      "erase",
+	MoveAddMark:    "addMark",
+	MoveRemoveMark: "removeMark",
+	MoveColour:     "colour",
+}
+
+func (k MoveKind) String() string {
+	if name, found := moveKindNames[k]; found {
+		return name
+	}
+	return fmt.Sprintf("MoveKind(%d)", uint8(k))
+}
+
+func (k MoveKind) MarshalText() ([]byte, error) {
+	name, found := moveKindNames[k]
+	if !found {
+		return nil, fmt.Errorf("unknown move kind %d", uint8(k))
+	}
+	return []byte(name), nil
+}
+
+func (k *MoveKind) UnmarshalText(text []byte) error {
+	for kind, name := range moveKindNames {
+		if name == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown move kind %q", text)
+}
+
+/*
+Move is a single change to one cell of a board. Value is the number placed or erased, the pencil mark added or
+removed, or the new colour. Previous is what the cell held before a MovePlace or MoveColour so that every move can be
+reversed, see Inverse. Moves are used both for what a player does and for the steps of the solver.
+*/
+type Move struct {
+	Kind     MoveKind `json:"kind"`
+	X        int      `json:"x"`
+	Y        int      `json:"y"`
+	Value    int      `json:"value"`
+	Previous int      `json:"previous,omitempty"`
+}
+
+// Inverse returns the move that undoes this one
+func (m Move) Inverse() Move {
+	switch m.Kind {
+	case MovePlace:
+		if m.Previous == 0 {
+			return Move{Kind: MoveErase, X: m.X, Y: m.Y, Value: m.Value}
+		}
+		return Move{Kind: MovePlace, X: m.X, Y: m.Y, Value: m.Previous, Previous: m.Value}
+	case MoveErase:
+		return Move{Kind: MovePlace, X: m.X, Y: m.Y, Value: m.Value}
+	case MoveAddMark:
+		return Move{Kind: MoveRemoveMark, X: m.X, Y: m.Y, Value: m.Value}
+	case MoveRemoveMark:
+		return Move{Kind: MoveAddMark, X: m.X, Y: m.Y, Value: m.Value}
+	case MoveColour:
+		return Move{Kind: MoveColour, X: m.X, Y: m.Y, Value: m.Previous, Previous: m.Value}
+	}
+	return m
+}
+
+func (m Move) String() string {
+	return fmt.Sprintf("%s %d at r%dc%d", m.Kind, m.Value, m.Y+1, m.X+1)
+}
@@ -0,0 +1,73 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMove_Inverse(t *testing.T) {
+	tests := []struct {
+		name string
+		move Move
+		want Move
+	}{
+		{
+			name: "place in an empty cell",
+			move: Move{Kind: MovePlace, X: 1, Y: 2, Value: 3},
+			want: Move{Kind: MoveErase, X: 1, Y: 2, Value: 3},
+		},
+		{
+			name: "place over a number",
+			move: Move{Kind: MovePlace, X: 1, Y: 2, Value: 3, Previous: 4},
+			want: Move{Kind: MovePlace, X: 1, Y: 2, Value: 4, Previous: 3},
+		},
+		{
+			name: "erase",
+			move: Move{Kind: MoveErase, X: 1, Y: 2, Value: 3},
+			want: Move{Kind: MovePlace, X: 1, Y: 2, Value: 3},
+		},
+		{
+			name: "add mark",
+			move: Move{Kind: MoveAddMark, X: 1, Y: 2, Value: 3},
+			want: Move{Kind: MoveRemoveMark, X: 1, Y: 2, Value: 3},
+		},
+		{
+			name: "remove mark",
+			move: Move{Kind: MoveRemoveMark, X: 1, Y: 2, Value: 3},
+			want: Move{Kind: MoveAddMark, X: 1, Y: 2, Value: 3},
+		},
+		{
+			name: "colour",
+			move: Move{Kind: MoveColour, X: 1, Y: 2, Value: 3, Previous: 1},
+			want: Move{Kind: MoveColour, X: 1, Y: 2, Value: 1, Previous: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.move.Inverse(); got != tt.want {
+				t.Errorf("Inverse() = %v, want %v", got, tt.want)
+			}
+			if got := tt.move.Inverse().Inverse(); got != tt.move {
+				t.Errorf("Inverse().Inverse() = %v, want %v", got, tt.move)
+			}
+		})
+	}
+}
+
+func TestMove_JSON(t *testing.T) {
+	move := Move{Kind: MoveRemoveMark, X: 4, Y: 5, Value: 6}
+	data, err := json.Marshal(move)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"kind":"removeMark","x":4,"y":5,"value":6}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+	var got Move
+	if err := json.Unmarshal(data, &got); err != nil || got != move {
+		t.Errorf("json.Unmarshal() = %v, %v, want %v", got, err, move)
+	}
+	if err := json.Unmarshal([]byte(`{"kind":"jump"}`), &got); err == nil {
+		t.Errorf("json.Unmarshal() error = nil for an unknown kind")
+	}
+}
@@ -0,0 +1,39 @@
+package generator
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+// dailyAttempts is high as hard puzzles are rare, about one in a thousand random puzzles
+const dailyAttempts = 20000
+
+/*
+DailySeed maps a calendar date and a difficulty to a seed. Only the year, month and day of the date in its own
+location count, so the same day gives the same seed wherever it is asked for.
+*/
+func DailySeed(date time.Time, difficulty solver.StrategyDifficulty) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(date.Format(time.DateOnly) + "/" + difficulty.String()))
+	return int64(h.Sum64())
+}
+
+/*
+Daily generates the puzzle of the day for the difficulty, every call for the same date and difficulty gives the same
+puzzle. Hard puzzles can take a while to find, difficulties that can not be generated fail with ErrTargetUnreachable
+right away, see Target.Check.
+*/
+func Daily(date time.Time, difficulty solver.StrategyDifficulty) (*Generated, error) {
+	return DailyContext(context.Background(), date, difficulty)
+}
+
+/*
+DailyContext works like Daily but stops with ErrTargetNotReached once the context is done. Only a search that ran all
+its attempts fails the same way every time, one stopped by the context may find the puzzle when asked again.
+*/
+func DailyContext(ctx context.Context, date time.Time, difficulty solver.StrategyDifficulty) (*Generated, error) {
+	return New(DailySeed(date, difficulty)).TargetPuzzleContext(ctx, TargetDifficulty(difficulty), dailyAttempts)
+}
@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+func TestDailySeed(t *testing.T) {
+	day := time.Date(2026, time.October, 19, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		date       time.Time
+		difficulty solver.StrategyDifficulty
+		same       bool
+	}{
+		{name: "later that day", date: day.Add(10 * time.Hour), difficulty: solver.StrategyDifficultyEasy, same: true},
+		{name: "next day", date: day.AddDate(0, 0, 1), difficulty: solver.StrategyDifficultyEasy, same: false},
+		{name: "other difficulty", date: day, difficulty: solver.StrategyDifficultyMedium, same: false},
+	}
+	seed := DailySeed(day, solver.StrategyDifficultyEasy)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DailySeed(tt.date, tt.difficulty); (got == seed) != tt.same {
+				t.Errorf("DailySeed() = %d, first day %d, want same %v", got, seed, tt.same)
+			}
+		})
+	}
+}
+
+func TestDaily(t *testing.T) {
+	day := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	first, err := Daily(day, solver.StrategyDifficultyEasy)
+	if err != nil {
+		t.Fatalf("Daily() error = %v", err)
+	}
+	second, _ := Daily(day, solver.StrategyDifficultyEasy)
+	if board.FormatLine(first.Puzzle) != board.FormatLine(second.Puzzle) {
+		t.Errorf("Daily() gave %s and %s for the same day", board.FormatLine(first.Puzzle), board.FormatLine(second.Puzzle))
+	}
+	if first.Rating.Difficulty != solver.StrategyDifficultyEasy {
+		t.Errorf("Daily() rated %s, want easy", first.Rating.Difficulty)
+	}
+	if _, err := Daily(day, solver.StrategyDifficultyVeryHard); !errors.Is(err, ErrTargetUnreachable) {
+		t.Errorf("Daily() of a very hard puzzle error = %v, want ErrTargetUnreachable", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := DailyContext(ctx, day, solver.StrategyDifficultyHard); !errors.Is(err, ErrTargetNotReached) ||
+		!errors.Is(err, context.Canceled) {
+		t.Errorf("DailyContext() error = %v once cancelled, want ErrTargetNotReached", err)
+	}
+}
+
+func TestGenerator_Seeded(t *testing.T) {
+	a, b := New(41), New(41)
+	for i := 0; i < 3; i++ {
+		solutionA, solutionB := a.Solution(), b.Solution()
+		if board.FormatLine(a.Puzzle(solutionA)) != board.FormatLine(b.Puzzle(solutionB)) {
+			t.Errorf("generators with the same seed made different puzzles")
+		}
+	}
+	if board.FormatLine(New(41).Solution()) == board.FormatLine(New(42).Solution()) {
+		t.Errorf("generators with different seeds made the same solution")
+	}
+}
@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"math/rand"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+/*
+Generator makes solutions and puzzles from its own source of random numbers. Two generators made with the same seed
+make the same solutions and puzzles when asked for them in the same order, which is what the daily puzzle relies on.
+A Generator must not be shared between goroutines. The Generate functions of the package each use a generator with a
+random seed.
+*/
+type Generator struct {
+	rng *rand.Rand
+}
+
+func New(seed int64) *Generator {
+	return &Generator{rng: rand.New(rand.NewSource(seed))}
+}
+
+// random returns a generator with a random seed for the package level functions
+func random() *Generator {
+	return New(rand.Int63())
+}
+
+// GenerateSolution is Generator.Solution with a random seed
+func GenerateSolution(constraints ...board.Constraint) *board.SudokuBoard {
+	return random().Solution(constraints...)
+}
+
+// GeneratePuzzle is Generator.Puzzle with a random seed
+func GeneratePuzzle(solution *board.SudokuBoard) *board.SudokuBoard {
+	return random().Puzzle(solution)
+}
+
+/*
+Solution fills an empty board with random numbers so that it follows the classic rules as well as the given
+constraints. It returns nil if the constraints can not be satisfied.
+*/
+func (g *Generator) Solution(constraints ...board.Constraint) *board.SudokuBoard {
+	b := (&board.SudokuBoard{}).AddConstraints(constraints...)
+	if !solver.SolveByFewestOptions(solver.GuessConfig(solver.NewSeededOrderGuesser(g.rng)), b) {
+		return nil
+	}
+	return b
+}
+
+/*
+Puzzle removes numbers from a solved board in a random order for as long as the puzzle keeps a single solution.
+Constraints of the solution are kept on the puzzle and taken into account, so variant puzzles usually end up with far
+fewer numbers than classic ones.
+*/
+func (g *Generator) Puzzle(solution *board.SudokuBoard) *board.SudokuBoard {
+	return g.SymmetricPuzzle(solution, SymmetryNone)
+}
@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+func TestGenerateSolution(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints []board.Constraint
+	}{
+		{
+			name: "classic",
+		},
+		{
+			name:        "chess",
+			constraints: []board.Constraint{board.AntiKnightConstraint{}, board.AntiKingConstraint{}},
+		},
+		{
+			name:        "non-consecutive",
+			constraints: []board.Constraint{board.NonConsecutiveConstraint{}},
+		},
+		{
+			name: "kropki",
+			constraints: []board.Constraint{board.KropkiConstraint{
+				White: []board.CellPair{{A: board.Cell{X: 0, Y: 0}, B: board.Cell{X: 1, Y: 0}}},
+				Black: []board.CellPair{{A: board.Cell{X: 4, Y: 4}, B: board.Cell{X: 4, Y: 5}}},
+			}},
+		},
+		{
+			name: "xv",
+			constraints: []board.Constraint{board.XVConstraint{
+				X: []board.CellPair{{A: board.Cell{X: 0, Y: 0}, B: board.Cell{X: 1, Y: 0}}},
+				V: []board.CellPair{{A: board.Cell{X: 4, Y: 4}, B: board.Cell{X: 4, Y: 5}}},
+			}},
+		},
+		{
+			name: "greater than",
+			constraints: []board.Constraint{board.GreaterThanConstraint{
+				Pairs: []board.CellPair{{A: board.Cell{X: 0, Y: 0}, B: board.Cell{X: 1, Y: 0}}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GenerateSolution(tt.constraints...)
+			if got == nil {
+				t.Fatalf("GenerateSolution() = nil")
+			}
+			if !board.IsSolved(got) {
+				t.Errorf("GenerateSolution() is not solved\n%v", got)
+			}
+		})
+	}
+}
+
+func TestGenerateSolution_Impossible(t *testing.T) {
+	impossible := board.GreaterThanConstraint{Pairs: []board.CellPair{
+		{A: board.Cell{X: 0, Y: 0}, B: board.Cell{X: 1, Y: 0}},
+		{A: board.Cell{X: 1, Y: 0}, B: board.Cell{X: 0, Y: 0}},
+	}}
+	if got := GenerateSolution(impossible); got != nil {
+		t.Errorf("GenerateSolution() = \n%v, want nil", got)
+	}
+}
+
+func TestGeneratePuzzle(t *testing.T) {
+	classic := GenerateSolution()
+	kropki := GenerateSolution()
+	kropki.AddConstraints(board.KropkiFromSolution(kropki, true))
+
+	tests := []struct {
+		name     string
+		solution *board.SudokuBoard
+	}{
+		{name: "classic", solution: classic},
+		{name: "negative kropki", solution: kropki},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			puzzle := GeneratePuzzle(tt.solution)
+			if !solver.IsUnique(puzzle) {
+				t.Errorf("GeneratePuzzle() is not unique\n%v", puzzle)
+			}
+			for x := 0; x < 9; x++ {
+				for y := 0; y < 9; y++ {
+					if v := puzzle.GetAt(x, y); v != 0 && v != tt.solution.GetAt(x, y) {
+						t.Errorf("GeneratePuzzle() changed (%d,%d) to %d", x, y, v)
+					}
+				}
+			}
+			if len(puzzle.Constraints()) != len(tt.solution.Constraints()) {
+				t.Errorf("GeneratePuzzle() dropped the constraints")
+			}
+		})
+	}
+}
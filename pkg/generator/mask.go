@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+// ErrNoUniquePuzzle is returned when no numbers giving a single solution were found for a mask
+var ErrNoUniquePuzzle = errors.New("no puzzle with a single solution found")
+
+// Mask marks the cells of a puzzle that hold a clue, it is indexed [y][x] like the board
+type Mask [9][9]bool
+
+// MaskOf returns the mask of the filled in cells of the board
+func MaskOf(b *board.SudokuBoard) Mask {
+	m := Mask{}
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			m[y][x] = b.GetAt(x, y) != 0
+		}
+	}
+	return m
+}
+
+/*
+ParseMask reads a mask from 81 characters in reading order, whitespace is ignored. A '.', '0' or '_' is an empty
+cell and any other character a clue, so both a puzzle in line form and a pattern drawn with 'x' can be used.
+*/
+func ParseMask(s string) (Mask, error) {
+	m := Mask{}
+	i := 0
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			continue
+		}
+		if i == 81 {
+			return Mask{}, fmt.Errorf("mask has more than 81 cells")
+		}
+		m[i/9][i%9] = r != '.' && r != '0' && r != '_'
+		i++
+	}
+	if i != 81 {
+		return Mask{}, fmt.Errorf("mask has %d cells, expected 81", i)
+	}
+	return m, nil
+}
+
+func (m Mask) String() string {
+	var result strings.Builder
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			if m[y][x] {
+				result.WriteByte('x')
+			} else {
+				result.WriteByte('.')
+			}
+		}
+	}
+	return result.String()
+}
+
+// Count returns the number of clues
+func (m Mask) Count() int {
+	count := 0
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			if m[y][x] {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// HasSymmetry checks the clues follow the symmetry
+func (m Mask) HasSymmetry(symmetry Symmetry) bool {
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			for _, c := range symmetry.Cells(board.Cell{X: x, Y: y}) {
+				if m[c.Y][c.X] != m[y][x] {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Apply returns a copy of the solution with only the cells of the mask filled in
+func (m Mask) Apply(solution *board.SudokuBoard) *board.SudokuBoard {
+	puzzle := solution.Copy()
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			if !m[y][x] {
+				puzzle.SetAt(x, y, 0)
+			}
+		}
+	}
+	return puzzle
+}
+
+// GeneratePuzzleFromMask is Generator.PuzzleFromMask with a random seed
+func GeneratePuzzleFromMask(mask Mask, attempts int, constraints ...board.Constraint) (*board.SudokuBoard, error) {
+	return random().PuzzleFromMask(mask, attempts, constraints...)
+}
+
+/*
+PuzzleFromMask fills the cells of the mask with numbers so that the puzzle has a single solution. It keeps
+generating solutions and trying them on the mask, so masks with few clues can take many attempts. It returns
+ErrNoUniquePuzzle if none of the attempts gave a single solution.
+*/
+func (g *Generator) PuzzleFromMask(mask Mask, attempts int, constraints ...board.Constraint) (*board.SudokuBoard, error) {
+	for i := 0; i < attempts; i++ {
+		solution := g.Solution(constraints...)
+		if solution == nil {
+			return nil, fmt.Errorf("the constraints can not be satisfied")
+		}
+		if puzzle := mask.Apply(solution); solver.IsUnique(puzzle) {
+			return puzzle, nil
+		}
+	}
+	return nil, fmt.Errorf("%d attempts for a mask of %d clues: %w", attempts, mask.Count(), ErrNoUniquePuzzle)
+}
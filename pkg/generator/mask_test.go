@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+func TestParseMask(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		count   int
+		wantErr bool
+	}{
+		{name: "pattern", s: "x........" + "........." + "........." + "........." + "....x...." + "........." + "........." + "........." + "........x", count: 3},
+		{name: "puzzle", s: "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79", count: 30},
+		{name: "too short", s: "x.x", wantErr: true},
+		{name: "too long", s: "x" + MaskOf(GenerateSolution()).String(), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMask(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Count() != tt.count {
+				t.Errorf("ParseMask() has %d clues, want %d", got.Count(), tt.count)
+			}
+		})
+	}
+}
+
+func TestGeneratePuzzleFromMask(t *testing.T) {
+	// a mask of a symmetric puzzle with a few extra clues so most fillings of it are unique
+	mask := MaskOf(GenerateSymmetricPuzzle(GenerateSolution(), SymmetryRotational180))
+	mask[4][4] = true
+	for y := 0; y < 9; y += 2 {
+		mask[y][y] = true
+		mask[8-y][8-y] = true
+	}
+
+	puzzle, err := GeneratePuzzleFromMask(mask, 1000)
+	if err != nil {
+		t.Fatalf("GeneratePuzzleFromMask() error = %v", err)
+	}
+	if MaskOf(puzzle) != mask {
+		t.Errorf("GeneratePuzzleFromMask() clues %s, want %s", MaskOf(puzzle), mask)
+	}
+	if !solver.IsUnique(puzzle) {
+		t.Errorf("GeneratePuzzleFromMask() is not unique\n%v", puzzle)
+	}
+}
+
+func TestGeneratePuzzleFromMask_NoUnique(t *testing.T) {
+	if _, err := GeneratePuzzleFromMask(Mask{}, 3); !errors.Is(err, ErrNoUniquePuzzle) {
+		t.Errorf("GeneratePuzzleFromMask() error = %v, want ErrNoUniquePuzzle", err)
+	}
+}
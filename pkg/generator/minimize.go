@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+var (
+	// ErrNoSolution is returned for a puzzle that can not be solved at all
+	ErrNoSolution = errors.New("the puzzle has no solution")
+	// ErrNotUnique is returned for a puzzle with more than one solution where a single one is needed
+	ErrNotUnique = errors.New("the puzzle has more than one solution")
+)
+
+// checkUnique returns ErrNoSolution or ErrNotUnique unless the puzzle has a single solution
+func checkUnique(puzzle *board.SudokuBoard) error {
+	switch solver.CountSolutions(puzzle, 2) {
+	case 0:
+		return ErrNoSolution
+	case 1:
+		return nil
+	}
+	return ErrNotUnique
+}
+
+/*
+Minimize removes every given that is not needed for the puzzle to keep its single solution, in reading order. No
+given of the result can be removed without allowing a second solution, though other minimal puzzles with fewer givens
+may exist. It returns ErrNoSolution or ErrNotUnique for a puzzle that does not have a single solution.
+*/
+func Minimize(puzzle *board.SudokuBoard) (*board.SudokuBoard, error) {
+	if err := checkUnique(puzzle); err != nil {
+		return nil, err
+	}
+	result := puzzle.Copy()
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			value := result.GetAt(x, y)
+			if value == 0 {
+				continue
+			}
+			result.SetAt(x, y, 0)
+			if !solver.IsUnique(result) {
+				result.SetAt(x, y, value)
+			}
+		}
+	}
+	return result, nil
+}
+
+// IsMinimal checks the puzzle has a single solution and loses it when any of its givens is removed
+func IsMinimal(puzzle *board.SudokuBoard) bool {
+	if checkUnique(puzzle) != nil {
+		return false
+	}
+	work := puzzle.Copy()
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			value := work.GetAt(x, y)
+			if value == 0 {
+				continue
+			}
+			work.SetAt(x, y, 0)
+			unique := solver.IsUnique(work)
+			work.SetAt(x, y, value)
+			if unique {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Clue is a number to add to a puzzle
+type Clue struct {
+	X     int `json:"x"`
+	Y     int `json:"y"`
+	Value int `json:"value"`
+}
+
+func (c Clue) String() string {
+	return fmt.Sprintf("%s=%d", board.Cell{X: c.X, Y: c.Y}, c.Value)
+}
+
+/*
+SuggestClues finds the fewest clues that give the puzzle a single solution, keeping the first solution the solver
+finds. Every other solution differs from it in at least one cell and one of those cells has to become a clue, so the
+search only tries those cells and looks at all sets of 1 clue, then 2 clues and so on up to maxClues. It returns
+ErrNoSolution for a puzzle without solutions and ErrNotUnique if more than maxClues would be needed.
+*/
+func SuggestClues(puzzle *board.SudokuBoard, maxClues int) ([]Clue, error) {
+	solutions := solver.FindSolutions(puzzle, 1)
+	if len(solutions) == 0 {
+		return nil, ErrNoSolution
+	}
+	work := puzzle.Copy()
+	for count := 0; count <= maxClues; count++ {
+		if clues, found := suggestClues(work, solutions[0], count); found {
+			return clues, nil
+		}
+	}
+	return nil, fmt.Errorf("more than %d clues needed: %w", maxClues, ErrNotUnique)
+}
+
+// suggestClues tries every way to add up to count clues from the solution to the puzzle
+func suggestClues(puzzle, solution *board.SudokuBoard, count int) ([]Clue, bool) {
+	solutions := solver.FindSolutions(puzzle, 2)
+	if len(solutions) == 1 {
+		return nil, true
+	} else if count == 0 {
+		return nil, false
+	}
+
+	other := solutions[0]
+	if len(board.Diff(other, solution)) == 0 {
+		other = solutions[1]
+	}
+	for _, c := range board.Diff(other, solution) {
+		value := solution.GetAt(c.X, c.Y)
+		puzzle.SetAt(c.X, c.Y, value)
+		clues, found := suggestClues(puzzle, solution, count-1)
+		puzzle.SetAt(c.X, c.Y, 0)
+		if found {
+			return append([]Clue{{X: c.X, Y: c.Y, Value: value}}, clues...), true
+		}
+	}
+	return nil, false
+}
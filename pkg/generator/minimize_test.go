@@ -0,0 +1,117 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+func TestMinimize(t *testing.T) {
+	solution := GenerateSolution()
+	// a unique puzzle with a few more givens than it needs
+	puzzle := GeneratePuzzle(solution)
+	for x := 0; x < 9; x++ {
+		puzzle.SetAt(x, 4, solution.GetAt(x, 4))
+	}
+
+	for _, b := range []*board.SudokuBoard{solution, puzzle} {
+		got, err := Minimize(b)
+		if err != nil {
+			t.Fatalf("Minimize() error = %v", err)
+		}
+		if !IsMinimal(got) {
+			t.Errorf("Minimize() is not minimal\n%v", got)
+		}
+		for _, c := range board.Diff(got, b) {
+			if got.GetAt(c.X, c.Y) != 0 {
+				t.Errorf("Minimize() changed %v", c)
+			}
+		}
+	}
+}
+
+func TestMinimize_Errors(t *testing.T) {
+	invalid := GenerateSolution()
+	invalid.SetAt(0, 0, invalid.GetAt(1, 0))
+	tests := []struct {
+		name string
+		b    *board.SudokuBoard
+		want error
+	}{
+		{name: "no solution", b: invalid, want: ErrNoSolution},
+		{name: "not unique", b: &board.SudokuBoard{}, want: ErrNotUnique},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Minimize(tt.b); !errors.Is(err, tt.want) {
+				t.Errorf("Minimize() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsMinimal(t *testing.T) {
+	solution := GenerateSolution()
+	if !IsMinimal(GeneratePuzzle(solution)) {
+		t.Errorf("IsMinimal() = false for a generated puzzle")
+	}
+	if IsMinimal(solution) {
+		t.Errorf("IsMinimal() = true for a solution")
+	}
+	if IsMinimal(&board.SudokuBoard{}) {
+		t.Errorf("IsMinimal() = true for an empty board")
+	}
+}
+
+func TestSuggestClues(t *testing.T) {
+	puzzle := GeneratePuzzle(GenerateSolution())
+	// removing givens of a minimal puzzle allows more solutions
+	removed := 0
+	for y := 0; y < 9 && removed < 2; y++ {
+		for x := 0; x < 9 && removed < 2; x++ {
+			if puzzle.GetAt(x, y) != 0 {
+				puzzle.SetAt(x, y, 0)
+				removed++
+			}
+		}
+	}
+	if solver.IsUnique(puzzle) {
+		t.Fatalf("puzzle is still unique")
+	}
+
+	// the clues come from the first solution, which need not be the one the givens were removed from
+	solution := solver.FindSolutions(puzzle, 1)[0]
+	clues, err := SuggestClues(puzzle, 6)
+	if err != nil {
+		t.Fatalf("SuggestClues() error = %v", err)
+	}
+	if len(clues) == 0 {
+		t.Errorf("SuggestClues() found no clues")
+	}
+	for _, c := range clues {
+		if solution.GetAt(c.X, c.Y) != c.Value || puzzle.GetAt(c.X, c.Y) != 0 {
+			t.Errorf("SuggestClues() clue %v is not an empty cell of the first solution", c)
+		}
+		puzzle.SetAt(c.X, c.Y, c.Value)
+	}
+	if !solver.IsUnique(puzzle) {
+		t.Errorf("puzzle with %v is not unique", clues)
+	}
+
+	if clues, err := SuggestClues(puzzle, 3); err != nil || len(clues) != 0 {
+		t.Errorf("SuggestClues() = %v, %v for a unique puzzle", clues, err)
+	}
+}
+
+func TestSuggestClues_Errors(t *testing.T) {
+	invalid := GenerateSolution()
+	invalid.SetAt(0, 0, invalid.GetAt(1, 0))
+	if _, err := SuggestClues(invalid, 3); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("SuggestClues() error = %v, want ErrNoSolution", err)
+	}
+	if _, err := SuggestClues(&board.SudokuBoard{}, 1); !errors.Is(err, ErrNotUnique) {
+		t.Errorf("SuggestClues() error = %v, want ErrNotUnique", err)
+	}
+}
@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"fmt"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+// Symmetry is a pattern the clues of a puzzle follow, the numbers of the clues do not have to follow it
+type Symmetry uint8
+
+const (
+	SymmetryNone Symmetry = iota
+	// SymmetryRotational180 keeps the clues the same when the board is turned half way around
+	SymmetryRotational180
+	// SymmetryRotational90 keeps the clues the same when the board is turned a quarter turn
+	SymmetryRotational90
+	// SymmetryMirror keeps the clues the same when the board is reflected left to right
+	SymmetryMirror
+	// SymmetryDiagonal keeps the clues the same when the board is reflected along the main diagonal
+	SymmetryDiagonal
+)
+
+var symmetryNames = map[Symmetry]string{
+	SymmetryNone:          "none",
+	SymmetryRotational180: "rotational180",
+	SymmetryRotational90:  "rotational90",
+	SymmetryMirror:        "mirror",
+	SymmetryDiagonal:      "diagonal",
+}
+
+func (s Symmetry) String() string {
+	if name, found := symmetryNames[s]; found {
+		return name
+	}
+	return fmt.Sprintf("Symmetry(%d)", uint8(s))
+}
+
+func (s Symmetry) MarshalText() ([]byte, error) {
+	name, found := symmetryNames[s]
+	if !found {
+		return nil, fmt.Errorf("unknown symmetry %d", uint8(s))
+	}
+	return []byte(name), nil
+}
+
+func (s *Symmetry) UnmarshalText(text []byte) error {
+	parsed, err := ParseSymmetry(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// ParseSymmetry reads the name of a symmetry as returned by String
+func ParseSymmetry(name string) (Symmetry, error) {
+	for s, n := range symmetryNames {
+		if n == name {
+			return s, nil
+		}
+	}
+	return SymmetryNone, fmt.Errorf("unknown symmetry %q", name)
+}
+
+// transform returns the move of the board the symmetry is about, applying it until a cell comes back gives its group
+func (s Symmetry) transform() board.Transform {
+	switch s {
+	case SymmetryRotational180:
+		return board.Rotate180()
+	case SymmetryRotational90:
+		return board.Rotate90()
+	case SymmetryMirror:
+		return board.MirrorHorizontal()
+	case SymmetryDiagonal:
+		return board.Transpose()
+	}
+	return board.Identity()
+}
+
+// Cells returns the cell together with every cell that has to be a clue if it is one
+func (s Symmetry) Cells(c board.Cell) []board.Cell {
+	t := s.transform()
+	result := []board.Cell{c}
+	for {
+		x, y := t.MapCell(result[len(result)-1].X, result[len(result)-1].Y)
+		if x == c.X && y == c.Y {
+			return result
+		}
+		result = append(result, board.Cell{X: x, Y: y})
+	}
+}
+
+// GenerateSymmetricPuzzle is Generator.SymmetricPuzzle with a random seed
+func GenerateSymmetricPuzzle(solution *board.SudokuBoard, symmetry Symmetry) *board.SudokuBoard {
+	return random().SymmetricPuzzle(solution, symmetry)
+}
+
+/*
+SymmetricPuzzle removes numbers from a solved board like Puzzle, but always removes a cell together with the cells the
+symmetry ties it to. The puzzle keeps a single solution and its clues follow the symmetry. As fewer removals are
+possible the puzzle usually has a few more numbers than one without symmetry.
+*/
+func (g *Generator) SymmetricPuzzle(solution *board.SudokuBoard, symmetry Symmetry) *board.SudokuBoard {
+	return g.removeClues(solution, symmetry, nil)
+}
+
+// removeClues does the work of SymmetricPuzzle, every removal tried is recorded if trace is not nil
+func (g *Generator) removeClues(solution *board.SudokuBoard, symmetry Symmetry, trace *Trace) *board.SudokuBoard {
+	puzzle := solution.Copy()
+	for _, cell := range g.rng.Perm(81) {
+		cells := symmetry.Cells(board.Cell{X: cell % 9, Y: cell / 9})
+		if puzzle.GetAt(cells[0].X, cells[0].Y) == 0 {
+			continue
+		}
+		for _, c := range cells {
+			puzzle.SetAt(c.X, c.Y, 0)
+		}
+		unique := solver.IsUnique(puzzle)
+		if trace != nil {
+			trace.record(solution, puzzle, cells, unique)
+		}
+		if !unique {
+			for _, c := range cells {
+				puzzle.SetAt(c.X, c.Y, solution.GetAt(c.X, c.Y))
+			}
+		}
+	}
+	return puzzle
+}
@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+func TestSymmetry_Cells(t *testing.T) {
+	tests := []struct {
+		symmetry Symmetry
+		cell     board.Cell
+		want     int
+	}{
+		{symmetry: SymmetryNone, cell: board.Cell{X: 1, Y: 2}, want: 1},
+		{symmetry: SymmetryRotational180, cell: board.Cell{X: 1, Y: 2}, want: 2},
+		{symmetry: SymmetryRotational180, cell: board.Cell{X: 4, Y: 4}, want: 1},
+		{symmetry: SymmetryRotational90, cell: board.Cell{X: 1, Y: 2}, want: 4},
+		{symmetry: SymmetryMirror, cell: board.Cell{X: 1, Y: 2}, want: 2},
+		{symmetry: SymmetryMirror, cell: board.Cell{X: 4, Y: 2}, want: 1},
+		{symmetry: SymmetryDiagonal, cell: board.Cell{X: 1, Y: 2}, want: 2},
+		{symmetry: SymmetryDiagonal, cell: board.Cell{X: 3, Y: 3}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.symmetry.String(), func(t *testing.T) {
+			got := tt.symmetry.Cells(tt.cell)
+			if len(got) != tt.want || got[0] != tt.cell {
+				t.Errorf("Cells(%v) = %v, want %d cells starting with it", tt.cell, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSymmetry(t *testing.T) {
+	for s := range symmetryNames {
+		got, err := ParseSymmetry(s.String())
+		if err != nil || got != s {
+			t.Errorf("ParseSymmetry(%q) = %v, %v", s.String(), got, err)
+		}
+	}
+	if _, err := ParseSymmetry("spiral"); err == nil {
+		t.Errorf("ParseSymmetry(\"spiral\") did not fail")
+	}
+}
+
+func TestGenerateSymmetricPuzzle(t *testing.T) {
+	solution := GenerateSolution()
+	for s := range symmetryNames {
+		t.Run(s.String(), func(t *testing.T) {
+			puzzle := GenerateSymmetricPuzzle(solution, s)
+			if !solver.IsUnique(puzzle) {
+				t.Errorf("GenerateSymmetricPuzzle() is not unique\n%v", puzzle)
+			}
+			if mask := MaskOf(puzzle); !mask.HasSymmetry(s) {
+				t.Errorf("GenerateSymmetricPuzzle() clues %s do not have the symmetry", mask)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+var (
+	// ErrTargetNotReached is returned when none of the attempts gave a puzzle matching the target
+	ErrTargetNotReached = errors.New("no puzzle matching the target found")
+	// ErrTargetUnreachable is returned for targets generation can not reach in reasonable time, see Target.Check
+	ErrTargetUnreachable = errors.New("target can not be generated")
+)
+
+/*
+maxTargetDifficulty is the hardest difficulty puzzles can be generated for. No strategy is very hard, and puzzles the
+strategies can not solve without guessing are too rare among random puzzles to search for.
+*/
+const maxTargetDifficulty = solver.StrategyDifficultyHard
+
+// constraintStrategies only apply to the constraints of variant puzzles, which generated puzzles do not have
+var constraintStrategies = map[solver.StrategyName]bool{
+	solver.StrategyNameThermometerStrategy: true,
+	solver.StrategyNameArrowStrategy:       true,
+	solver.StrategyNameSandwichStrategy:    true,
+}
+
+/*
+Target describes the puzzle wanted from GenerateTargetPuzzle. The hardest strategy the solver needs has to be between
+MinDifficulty and MaxDifficulty. If Strategy is set the solver also has to use it, so the range has to reach the
+difficulty of the strategy, TargetStrategy sets it to exactly that. With Trace set the puzzle comes with a Trace of how
+it was made, which makes every attempt a lot slower.
+*/
+type Target struct {
+	MinDifficulty solver.StrategyDifficulty
+	MaxDifficulty solver.StrategyDifficulty
+	Strategy      solver.StrategyName
+	Symmetry      Symmetry
+	Trace         bool
+}
+
+// TargetDifficulty wants a puzzle of exactly the difficulty
+func TargetDifficulty(d solver.StrategyDifficulty) Target {
+	return Target{MinDifficulty: d, MaxDifficulty: d}
+}
+
+// TargetStrategy wants a puzzle that needs the strategy and nothing harder
+func TargetStrategy(name solver.StrategyName) Target {
+	return Target{MinDifficulty: name.Difficulty(), MaxDifficulty: name.Difficulty(), Strategy: name}
+}
+
+/*
+Check makes sure the target can be generated, it fails with ErrTargetUnreachable if it is out of reach: a difficulty
+or strategy harder than generation goes, or a strategy that only applies to constraints.
+*/
+func (t Target) Check() error {
+	if t.MinDifficulty > t.MaxDifficulty {
+		return fmt.Errorf("difficulty range %s to %s is empty", t.MinDifficulty, t.MaxDifficulty)
+	}
+	if t.MinDifficulty > maxTargetDifficulty {
+		return fmt.Errorf("%w: puzzles are generated up to %s, not %s", ErrTargetUnreachable, maxTargetDifficulty,
+			t.MinDifficulty)
+	}
+	if t.Strategy == "" {
+		return nil
+	}
+	if constraintStrategies[t.Strategy] {
+		return fmt.Errorf("%w: %s only applies to constraints, generated puzzles have none", ErrTargetUnreachable,
+			t.Strategy)
+	}
+	if d := t.Strategy.Difficulty(); d > maxTargetDifficulty {
+		return fmt.Errorf("%w: puzzles are generated up to %s, %s is %s", ErrTargetUnreachable, maxTargetDifficulty,
+			t.Strategy, d)
+	} else if d > t.MaxDifficulty {
+		return fmt.Errorf("%s is %s, outside of the difficulty range %s to %s", t.Strategy, d, t.MinDifficulty,
+			t.MaxDifficulty)
+	}
+	return nil
+}
+
+// Matches checks the rating of a puzzle is what the target wants
+func (t Target) Matches(rating solver.Rating) bool {
+	if rating.Difficulty < t.MinDifficulty || rating.Difficulty > t.MaxDifficulty {
+		return false
+	}
+	return t.Strategy == "" || rating.Uses(t.Strategy)
+}
+
+// Generated is a puzzle made to match a target together with how it rated and how many attempts it took
+type Generated struct {
+	Puzzle   *board.SudokuBoard `json:"puzzle"`
+	Solution *board.SudokuBoard `json:"solution"`
+	Rating   solver.Rating      `json:"rating"`
+	Attempts int                `json:"attempts"`
+	Trace    *Trace             `json:"trace,omitempty"`
+}
+
+// GenerateTargetPuzzle is Generator.TargetPuzzle with a random seed
+func GenerateTargetPuzzle(target Target, maxAttempts int) (*Generated, error) {
+	return random().TargetPuzzle(target, maxAttempts)
+}
+
+// GenerateTargetPuzzleContext is Generator.TargetPuzzleContext with a random seed
+func GenerateTargetPuzzleContext(ctx context.Context, target Target) (*Generated, error) {
+	return random().TargetPuzzleContext(ctx, target, 0)
+}
+
+/*
+TargetPuzzle generates puzzles until one matches the target, each attempt is a new solution with clues removed as
+SymmetricPuzzle does and rated by the strategy solver. It returns ErrTargetNotReached after maxAttempts, and
+ErrTargetUnreachable without trying if the target fails Check.
+Only classic puzzles can be targeted as the strategies do not handle every constraint.
+*/
+func (g *Generator) TargetPuzzle(target Target, maxAttempts int) (*Generated, error) {
+	return g.TargetPuzzleContext(context.Background(), target, maxAttempts)
+}
+
+/*
+TargetPuzzleContext works like TargetPuzzle but also stops with ErrTargetNotReached once the context is done, which
+bounds the search by time rather than attempts. A maxAttempts of 0 leaves the attempts unbounded. Only the number of
+attempts makes the puzzle of a seeded generator reproducible, when the context stops the search varies with the speed
+of the machine.
+*/
+func (g *Generator) TargetPuzzleContext(ctx context.Context, target Target, maxAttempts int) (*Generated, error) {
+	if err := target.Check(); err != nil {
+		return nil, err
+	}
+	for attempt := 1; maxAttempts == 0 || attempt <= maxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("%d attempts: %w: %w", attempt-1, ErrTargetNotReached, err)
+		}
+		solution := g.Solution()
+		var trace *Trace
+		var puzzle *board.SudokuBoard
+		if target.Trace {
+			trace = g.TracedPuzzle(solution, target.Symmetry)
+			puzzle = trace.Puzzle
+		} else {
+			puzzle = g.SymmetricPuzzle(solution, target.Symmetry)
+		}
+		rating := solver.Rate(puzzle)
+		if target.Matches(rating) {
+			return &Generated{Puzzle: puzzle, Solution: solution, Rating: rating, Attempts: attempt, Trace: trace}, nil
+		}
+	}
+	return nil, fmt.Errorf("%d attempts: %w", maxAttempts, ErrTargetNotReached)
+}
@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+func TestTarget_Matches(t *testing.T) {
+	singles := solver.Rating{
+		Difficulty: solver.StrategyDifficultyEasy,
+		Strategies: []solver.StrategyName{solver.StrategyNameLastInRowStrategy},
+	}
+	xWing := solver.Rating{
+		Difficulty: solver.StrategyDifficultyHard,
+		Strategies: []solver.StrategyName{solver.StrategyNameLastInRowStrategy, solver.StrategyNameXWingStrategy},
+	}
+	guessing := solver.Rating{
+		Difficulty: solver.StrategyDifficultyImpossible,
+		Strategies: []solver.StrategyName{solver.StrategyNameXWingStrategy, solver.StrategyNamePsychicStrategy},
+	}
+	tests := []struct {
+		name   string
+		target Target
+		rating solver.Rating
+		want   bool
+	}{
+		{name: "easy", target: TargetDifficulty(solver.StrategyDifficultyEasy), rating: singles, want: true},
+		{name: "too hard", target: TargetDifficulty(solver.StrategyDifficultyEasy), rating: xWing, want: false},
+		{name: "too easy", target: TargetDifficulty(solver.StrategyDifficultyHard), rating: singles, want: false},
+		{
+			name:   "band",
+			target: Target{MinDifficulty: solver.StrategyDifficultyMedium, MaxDifficulty: solver.StrategyDifficultyHard},
+			rating: xWing,
+			want:   true,
+		},
+		{name: "strategy", target: TargetStrategy(solver.StrategyNameXWingStrategy), rating: xWing, want: true},
+		{name: "strategy missing", target: TargetStrategy(solver.StrategyNameNakedPairStrategy), rating: xWing, want: false},
+		{name: "strategy and harder", target: TargetStrategy(solver.StrategyNameXWingStrategy), rating: guessing, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.target.Matches(tt.rating); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateTargetPuzzle(t *testing.T) {
+	tests := []struct {
+		name   string
+		target Target
+	}{
+		{name: "easy", target: TargetDifficulty(solver.StrategyDifficultyEasy)},
+		{name: "last candidate", target: TargetStrategy(solver.StrategyNameLastCandidateStrategy)},
+		{
+			name: "symmetric medium",
+			target: Target{
+				MinDifficulty: solver.StrategyDifficultyMedium,
+				MaxDifficulty: solver.StrategyDifficultyMedium,
+				Symmetry:      SymmetryRotational180,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateTargetPuzzle(tt.target, 500)
+			if err != nil {
+				t.Fatalf("GenerateTargetPuzzle() error = %v", err)
+			}
+			if got.Attempts < 1 || got.Attempts > 500 {
+				t.Errorf("GenerateTargetPuzzle() took %d attempts", got.Attempts)
+			}
+			if rating := solver.Rate(got.Puzzle); !tt.target.Matches(rating) {
+				t.Errorf("GenerateTargetPuzzle() puzzle rates %v", rating)
+			}
+			if !solver.IsUnique(got.Puzzle) || !MaskOf(got.Puzzle).HasSymmetry(tt.target.Symmetry) {
+				t.Errorf("GenerateTargetPuzzle() puzzle is not unique or not symmetric\n%v", got.Puzzle)
+			}
+		})
+	}
+}
+
+func TestGenerateTargetPuzzle_NotReached(t *testing.T) {
+	// no strategy is very hard and puzzles needing guesses are too rare
+	for _, target := range []Target{
+		TargetDifficulty(solver.StrategyDifficultyVeryHard),
+		TargetDifficulty(solver.StrategyDifficultyImpossible),
+		TargetStrategy(solver.StrategyNamePsychicStrategy),
+		TargetStrategy(solver.StrategyNameThermometerStrategy),
+		TargetStrategy(solver.StrategyNameArrowStrategy),
+		TargetStrategy(solver.StrategyNameSandwichStrategy),
+	} {
+		if _, err := GenerateTargetPuzzle(target, 3); !errors.Is(err, ErrTargetUnreachable) {
+			t.Errorf("GenerateTargetPuzzle(%+v) error = %v, want ErrTargetUnreachable", target, err)
+		}
+	}
+	for _, target := range []Target{
+		{MinDifficulty: solver.StrategyDifficultyHard},
+		// the range stays easy, which is too easy for the strategy
+		{Strategy: solver.StrategyNameXWingStrategy},
+	} {
+		_, err := GenerateTargetPuzzle(target, 3)
+		if err == nil || errors.Is(err, ErrTargetNotReached) || errors.Is(err, ErrTargetUnreachable) {
+			t.Errorf("GenerateTargetPuzzle(%+v) error = %v, want the target refused", target, err)
+		}
+	}
+	// a seeded generator makes the same puzzles, none of the first of this one are hard
+	_, err := New(1).TargetPuzzle(TargetDifficulty(solver.StrategyDifficultyHard), 1)
+	if !errors.Is(err, ErrTargetNotReached) {
+		t.Errorf("TargetPuzzle() error = %v, want ErrTargetNotReached", err)
+	}
+}
+
+func TestGenerateTargetPuzzleContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	target := TargetDifficulty(solver.StrategyDifficultyEasy)
+	if _, err := GenerateTargetPuzzleContext(ctx, target); !errors.Is(err, ErrTargetNotReached) ||
+		!errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GenerateTargetPuzzleContext() error = %v, want ErrTargetNotReached", err)
+	}
+	got, err := GenerateTargetPuzzleContext(context.Background(), TargetDifficulty(solver.StrategyDifficultyEasy))
+	if err != nil || got.Rating.Difficulty != solver.StrategyDifficultyEasy {
+		t.Errorf("GenerateTargetPuzzleContext() = %v, %v", got, err)
+	}
+}
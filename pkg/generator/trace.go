@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+/*
+Trace records how a puzzle was made: the solved grid it started from and every removal that was tried, in order.
+Removals that left more than one solution were undone, the others are rated again so the trace shows how the puzzle
+got harder. It is meant to be shown to players and is written to JSON as it is.
+*/
+type Trace struct {
+	Seed     *board.SudokuBoard `json:"seed"`
+	Symmetry Symmetry           `json:"symmetry"`
+	Steps    []TraceStep        `json:"steps"`
+	Puzzle   *board.SudokuBoard `json:"puzzle"`
+	Rating   solver.Rating      `json:"rating"`
+}
+
+// TraceStep is one removal of clues, the cells the symmetry ties together are removed in the same step
+type TraceStep struct {
+	Cells []TraceCell `json:"cells"`
+	// Unique tells if the puzzle still had a single solution, if not the clues were put back
+	Unique bool `json:"unique"`
+	// Clues is the number of clues left after the step
+	Clues int `json:"clues"`
+	// Rating is that of the puzzle after a removal that was kept
+	Rating *solver.Rating `json:"rating,omitempty"`
+}
+
+// TraceCell is a cell and the number that was removed from it
+type TraceCell struct {
+	X     int `json:"x"`
+	Y     int `json:"y"`
+	Value int `json:"value"`
+}
+
+// GenerateTracedPuzzle is Generator.TracedPuzzle with a random seed
+func GenerateTracedPuzzle(solution *board.SudokuBoard, symmetry Symmetry) *Trace {
+	return random().TracedPuzzle(solution, symmetry)
+}
+
+// TracedPuzzle works like SymmetricPuzzle and records a Trace of it, rating every step is much slower
+func (g *Generator) TracedPuzzle(solution *board.SudokuBoard, symmetry Symmetry) *Trace {
+	trace := &Trace{Seed: solution.Copy(), Symmetry: symmetry}
+	trace.Puzzle = g.removeClues(solution, symmetry, trace)
+	trace.Rating = solver.Rate(trace.Puzzle)
+	return trace
+}
+
+// record adds the removal of cells from the puzzle, the puzzle has the cells removed
+func (t *Trace) record(solution, puzzle *board.SudokuBoard, cells []board.Cell, unique bool) {
+	step := TraceStep{Unique: unique, Clues: MaskOf(puzzle).Count()}
+	for _, c := range cells {
+		step.Cells = append(step.Cells, TraceCell{X: c.X, Y: c.Y, Value: solution.GetAt(c.X, c.Y)})
+	}
+	if unique {
+		rating := solver.Rate(puzzle)
+		step.Rating = &rating
+	} else {
+		step.Clues += len(cells)
+	}
+	t.Steps = append(t.Steps, step)
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+func TestGenerateTracedPuzzle(t *testing.T) {
+	solution := GenerateSolution()
+	trace := GenerateTracedPuzzle(solution, SymmetryRotational180)
+	if board.FormatLine(trace.Seed) != board.FormatLine(solution) {
+		t.Errorf("Seed = %s, want %s", board.FormatLine(trace.Seed), board.FormatLine(solution))
+	}
+
+	// making the kept removals on the seed gives the puzzle
+	puzzle := trace.Seed.Copy()
+	var last *solver.Rating
+	for i, step := range trace.Steps {
+		if step.Unique != (step.Rating != nil) {
+			t.Errorf("step %d: unique %v with rating %v", i, step.Unique, step.Rating)
+		}
+		if !step.Unique {
+			continue
+		}
+		for _, c := range step.Cells {
+			if puzzle.GetAt(c.X, c.Y) != c.Value {
+				t.Errorf("step %d: cell %d,%d holds %d, not %d", i, c.X, c.Y, puzzle.GetAt(c.X, c.Y), c.Value)
+			}
+			puzzle.SetAt(c.X, c.Y, 0)
+		}
+		if clues := MaskOf(puzzle).Count(); clues != step.Clues {
+			t.Errorf("step %d: %d clues, want %d", i, step.Clues, clues)
+		}
+		last = step.Rating
+	}
+	if board.FormatLine(puzzle) != board.FormatLine(trace.Puzzle) {
+		t.Errorf("removals give %s, want %s", board.FormatLine(puzzle), board.FormatLine(trace.Puzzle))
+	}
+	if !solver.IsUnique(trace.Puzzle) || !MaskOf(trace.Puzzle).HasSymmetry(SymmetryRotational180) {
+		t.Errorf("puzzle is not unique or not symmetric\n%v", trace.Puzzle)
+	}
+	if last == nil || !reflect.DeepEqual(*last, trace.Rating) {
+		t.Errorf("last step rated %v, puzzle rated %v", last, trace.Rating)
+	}
+}
+
+func TestTrace_JSON(t *testing.T) {
+	trace := GenerateTracedPuzzle(GenerateSolution(), SymmetryMirror)
+	data, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	got := &Trace{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Steps, trace.Steps) || got.Symmetry != trace.Symmetry ||
+		!reflect.DeepEqual(got.Rating, trace.Rating) || board.FormatLine(got.Puzzle) != board.FormatLine(trace.Puzzle) {
+		t.Errorf("Unmarshal() does not give the trace back\n%s", data)
+	}
+}
+
+func TestGenerateTargetPuzzle_Trace(t *testing.T) {
+	target := TargetDifficulty(solver.StrategyDifficultyEasy)
+	target.Trace = true
+	got, err := GenerateTargetPuzzle(target, 500)
+	if err != nil {
+		t.Fatalf("GenerateTargetPuzzle() error = %v", err)
+	}
+	if got.Trace == nil || board.FormatLine(got.Trace.Puzzle) != board.FormatLine(got.Puzzle) {
+		t.Errorf("GenerateTargetPuzzle() trace does not match the puzzle")
+	}
+}
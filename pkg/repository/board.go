@@ -1,159 +1,846 @@
 package repository
 
 import (
-	"encoding/binary"
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"math/rand"
-	osConst "os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"droidkfx.com/sudoku/pkg/board"
 	"github.com/spf13/afero"
 )
 
 const dbBoardFile = "boards.bin"
-const boardDataBytes = 41
-const lowMask uint8 = 0b0000_1111
-const highMask uint8 = 0b1111_0000
+const dbConstraintFile = "constraints.txt"
+const boardDataBytes = board.BoardBinaryBytes
 
+// eachChunkRecords is the number of records Each reads at once
+const eachChunkRecords = 256
+
+var (
+	// ErrNotFound is returned for ids without a board
+	ErrNotFound = errors.New("board not found")
+	// ErrCorrupt is returned when a file or record of the repository can not be read back
+	ErrCorrupt = errors.New("corrupt repository")
+	// ErrReadOnly is returned when saving to a repository whose files can not be written
+	ErrReadOnly = errors.New("repository is read only")
+	// ErrLocked is returned when another process holds the repository for too long
+	ErrLocked = errors.New("repository is locked")
+)
+
+/*
+SudokuBoardRepo stores boards under consecutive ids starting at 0. A board that was removed keeps its id, reading it
+returns ErrNotFound and it is left out of lists and queries. Every method wraps ErrNotFound, ErrCorrupt,
+ErrReadOnly or ErrLocked in the errors it returns where they apply, other errors come from the file system. The
+methods are safe to call from several goroutines, and several processes may open the same repository.
+*/
 type SudokuBoardRepo interface {
-	GetRandom() (int, *board.SudokuBoard)
-	GetByNumber(n int) (int, *board.SudokuBoard)
-	SaveNew(sudokuBoard *board.SudokuBoard)
-	SaveAll(sudokuBoards []*board.SudokuBoard)
+	// Count returns the number of ids given out, the ids are 0 to Count-1 including those of removed boards
+	Count() (int, error)
+	// List returns up to limit boards starting at id offset, fewer if the repository ends first
+	List(offset, limit int) ([]StoredBoard, error)
+	// Each calls visit with the boards in order starting at id offset until visit returns false, visit must not save
+	Each(offset int, visit func(StoredBoard) bool) error
+	// Find returns up to limit of the boards the query selects, skipping the first offset of them
+	Find(q Query, offset, limit int) ([]StoredBoard, error)
+	// CountMatching returns the number of boards the query selects
+	CountMatching(q Query) (int, error)
+	GetRandom() (int, *board.SudokuBoard, error)
+	// GetRandomMatching returns one of the boards the query selects, ErrNotFound if there are none
+	GetRandomMatching(q Query) (int, *board.SudokuBoard, error)
+	// GetRandomStored works like GetRandomMatching but returns the board with its metadata, both read at once
+	GetRandomStored(q Query) (StoredBoard, error)
+	GetByNumber(n int) (int, *board.SudokuBoard, error)
+	// GetStored returns board n with its metadata, both read at once
+	GetStored(n int) (StoredBoard, error)
+	// GetMetadata reads what is known about the board without reading the board itself
+	GetMetadata(n int) (int, Metadata, error)
+	// GetSolution returns the solution stored with the board, it is blank unless the board has a single solution
+	GetSolution(n int) (int, *board.SudokuBoard, error)
+	// SaveNew saves the board and returns its id
+	SaveNew(sudokuBoard *board.SudokuBoard) (int, error)
+	SaveAll(sudokuBoards []*board.SudokuBoard) error
+	// SaveAllFrom saves the boards with their solution and metadata, noting where they come from
+	SaveAllFrom(source string, sudokuBoards []*board.SudokuBoard) error
+	// Update replaces board id and describes it again, its source and creation time are kept
+	Update(id int, sudokuBoard *board.SudokuBoard) error
+	// Delete removes board id, the id is not given to another board
+	Delete(id int) error
 }
 
-func NewSudokuBoardRepo(dbLocation string) (SudokuBoardRepo, func()) {
+func NewSudokuBoardRepo(dbLocation string) (SudokuBoardRepo, func() error, error) {
 	return NewSudokuBoardRepoUsingFs(afero.NewBasePathFs(afero.NewOsFs(), dbLocation))
 }
 
-func NewSudokuBoardRepoUsingFs(fileSystem afero.Fs) (SudokuBoardRepo, func()) {
-	dbFile, err := fileSystem.OpenFile(dbBoardFile, osConst.O_CREATE|osConst.O_RDWR, 0666)
+// StoredBoard is a board of the repository with its id and what is known about it
+type StoredBoard struct {
+	Id       int                `json:"id"`
+	Board    *board.SudokuBoard `json:"board"`
+	Metadata Metadata           `json:"metadata"`
+}
+
+/*
+NewSudokuBoardRepoUsingFs opens the repository in the file system, creating its files if needed. If the files can not
+be written the repository is opened read only, see openFile, and saving returns ErrReadOnly.
+*/
+func NewSudokuBoardRepoUsingFs(fileSystem afero.Fs) (SudokuBoardRepo, func() error, error) {
+	constraintFile, readOnly, err := openFile(fileSystem, dbConstraintFile)
 	if err != nil {
-		panic(err)
+		return nil, nil, err
 	}
 
 	s := &sudokuBoardFileRepo{
-		home: dbFile,
+		constraints:         constraintFile,
+		constraintsReadOnly: readOnly,
+		now:                 time.Now,
+		lock:                newFileLock(fileSystem),
+	}
+	// another process could be upgrading or saving to the files while they are opened
+	err = s.locked(func() error {
+		// a save that did not finish is finished or undone before anything is read
+		if err := recoverJournal(fileSystem, readOnly); err != nil {
+			return err
+		}
+		// the constraints are needed to upgrade the records of older versions
+		if err := s.loadConstraints(); err != nil {
+			return err
+		}
+		format := boardFormat
+		format.older = map[uint16]recordUpgrade{
+			1: {recordSize: boardDataBytes, upgrade: s.upgradeRecord},
+			2: {recordSize: boardRecordBytes - boardChecksumBytes, upgrade: func(_ int, record []byte) []byte {
+				return sealRecord(record)
+			}},
+		}
+		s.home, err = openRecordFile(fileSystem, dbBoardFile, format)
+		if err != nil {
+			return err
+		}
+		if err := s.home.truncateTail(); err != nil {
+			_ = s.home.close()
+			return err
+		}
+		if err := s.truncateConstraints(s.home.count()); err != nil {
+			_ = s.home.close()
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		_ = constraintFile.Close()
+		return nil, nil, err
 	}
+	s.journal = newJournal(fileSystem, map[string]afero.File{dbBoardFile: s.home.file, dbConstraintFile: constraintFile})
 
-	return s, s.shutdown
+	return s, s.shutdown, nil
 }
 
+/*
+sudokuBoardFileRepo stores each board with its solution and metadata as a fixed size record in boards.bin, the id of
+a board is the index of its record, see recordFile and boardFormat. Variant constraints do not fit in a fixed size
+record so they are kept in constraints.txt, one line per board that has any in the form "<id> <constraints>", see
+board.FormatConstraints. Updating a board appends a line that replaces the earlier ones, with no constraints if it has
+none left. Saves go through journal so the boards of a batch are all saved or none are. A deleted board leaves a
+removed record behind, a tombstone, until the repository is compacted, see CompactSudokuBoardRepo.
+
+Goroutines share the repository through mu, readers hold it for reading and savers for writing. Processes share it
+through lock, which a saver holds while it writes and a reader takes to catch up with what other processes saved, see
+rlock. A repository that is read only can not take lock and catches up without it.
+*/
 type sudokuBoardFileRepo struct {
-	home afero.File
+	mu                  sync.RWMutex
+	lock                *fileLock
+	journal             *journal
+	home                *recordFile
+	constraints         afero.File
+	constraintsReadOnly bool
+	constraintsById     map[int]string
+	now                 func() time.Time
+	// index is built by the first query, see loadIndex, indexMu keeps readers from building it together
+	indexMu sync.Mutex
+	index   *boardIndex
+}
+
+func (s *sudokuBoardFileRepo) shutdown() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.home.close()
+	if !s.constraintsReadOnly {
+		err = errors.Join(err, s.constraints.Sync())
+	}
+	return errors.Join(err, s.constraints.Close())
 }
 
-func (s *sudokuBoardFileRepo) shutdown() {
-	_ = s.home.Sync()
-	_ = s.home.Close()
+func (s *sudokuBoardFileRepo) readOnly() bool {
+	return s.home.readOnly || s.constraintsReadOnly
 }
 
-func (s *sudokuBoardFileRepo) GetRandom() (int, *board.SudokuBoard) {
-	dataLength := int64(binary.Size(byte(0)) * 81)
-	fStat, err := s.home.Stat()
+/*
+rlock takes mu for reading. If another process saved boards since the repository last looked it first takes mu for
+writing and catches up under the file lock, see catchUp. A caller that got no error has to call mu.RUnlock.
+*/
+func (s *sudokuBoardFileRepo) rlock() error {
+	s.mu.RLock()
+	behind, err := s.home.behind()
+	if err == nil && !behind {
+		return nil
+	}
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	err = s.locked(s.catchUp)
+	s.mu.Unlock()
 	if err != nil {
-		panic(err)
+		return err
+	}
+	s.mu.RLock()
+	return nil
+}
+
+// locked calls f holding the file lock, a read only repository can not create the lock so it calls f without
+func (s *sudokuBoardFileRepo) locked(f func() error) (err error) {
+	if s.constraintsReadOnly || (s.home != nil && s.home.readOnly) {
+		return f()
+	}
+	if err := s.lock.lock(); err != nil {
+		return err
 	}
+	defer func() {
+		err = errors.Join(err, s.lock.unlock())
+	}()
+	return f()
+}
 
-	fSize := fStat.Size()
-	if fSize < dataLength {
-		return 0, board.FromNumbers([9][9]int{})
+/*
+catchUp reads the header and the constraints again if other processes saved, updated or removed boards, mu must be
+held for writing
+*/
+func (s *sudokuBoardFileRepo) catchUp() error {
+	count, generation := s.home.count(), s.home.header.Generation
+	if err := s.home.refresh(); err != nil {
+		return err
+	}
+	if s.home.count() == count && s.home.header.Generation == generation {
+		return nil
 	}
+	s.index = nil
+	return s.loadConstraints()
+}
 
-	loadedBoard, id := s.loadBoard(rand.Intn(int(fSize / dataLength)))
-	return id, s.dataToBoard(loadedBoard)
+func (s *sudokuBoardFileRepo) Count() (int, error) {
+	if err := s.rlock(); err != nil {
+		return 0, err
+	}
+	defer s.mu.RUnlock()
+	return s.home.count(), nil
 }
 
-func (s *sudokuBoardFileRepo) GetByNumber(n int) (int, *board.SudokuBoard) {
-	loadedBoard, id := s.loadBoard(n)
-	return id, s.dataToBoard(loadedBoard)
+func (s *sudokuBoardFileRepo) List(offset, limit int) ([]StoredBoard, error) {
+	if err := s.rlock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+	return s.list(offset, limit)
 }
 
-func (s *sudokuBoardFileRepo) SaveNew(sudokuBoard *board.SudokuBoard) {
-	s.SaveAll([]*board.SudokuBoard{sudokuBoard})
+func (s *sudokuBoardFileRepo) list(offset, limit int) ([]StoredBoard, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("negative limit %d", limit)
+	}
+	boards := make([]StoredBoard, 0, min(limit, max(s.home.count()-offset, 0)))
+	if limit == 0 {
+		return boards, nil
+	}
+	err := s.each(offset, func(b StoredBoard) bool {
+		boards = append(boards, b)
+		return len(boards) < limit
+	})
+	return boards, err
 }
 
-func (s *sudokuBoardFileRepo) SaveAll(sudokuBoards []*board.SudokuBoard) {
-	data := make([]byte, 0, boardDataBytes*len(sudokuBoards))
-	for _, b := range sudokuBoards {
-		data = append(data, s.boardToData(b)...)
+func (s *sudokuBoardFileRepo) Each(offset int, visit func(StoredBoard) bool) error {
+	if err := s.rlock(); err != nil {
+		return err
+	}
+	defer s.mu.RUnlock()
+	return s.each(offset, visit)
+}
+
+func (s *sudokuBoardFileRepo) each(offset int, visit func(StoredBoard) bool) error {
+	var decodeErr error
+	err := s.eachRecord(offset, func(id int, record []byte) bool {
+		if isRemoved(record) {
+			return true
+		}
+		stored, err := s.recordToStored(id, record)
+		if err != nil {
+			decodeErr = err
+			return false
+		}
+		return visit(stored)
+	})
+	return errors.Join(err, decodeErr)
+}
+
+// eachRecord calls visit with the records in order starting at offset until visit returns false
+func (s *sudokuBoardFileRepo) eachRecord(offset int, visit func(id int, record []byte) bool) error {
+	if offset < 0 {
+		return fmt.Errorf("board %d: %w", offset, ErrNotFound)
+	}
+	// records are read a chunk at a time rather than one by one
+	count := s.home.count()
+	for first := offset; first < count; first += eachChunkRecords {
+		n := min(eachChunkRecords, count-first)
+		data, err := s.home.readRange(first, n)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if !visit(first+i, data[i*boardRecordBytes:(i+1)*boardRecordBytes]) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (s *sudokuBoardFileRepo) Find(q Query, offset, limit int) ([]StoredBoard, error) {
+	if err := s.rlock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+	if offset < 0 || limit < 0 {
+		return nil, fmt.Errorf("negative offset %d or limit %d", offset, limit)
+	}
+	ids, err := s.findIds(q)
+	if err != nil {
+		return nil, err
 	}
-	s.saveToEnd(data)
+
+	ids = ids[min(offset, len(ids)):min(offset+limit, len(ids))]
+	boards := make([]StoredBoard, 0, len(ids))
+	for _, id := range ids {
+		record, err := s.home.read(id)
+		if err != nil {
+			return nil, err
+		}
+		// the index may be older than the record, a board removed since is left out of the page
+		if isRemoved(record) {
+			continue
+		}
+		stored, err := s.recordToStored(id, record)
+		if err != nil {
+			return nil, err
+		}
+		boards = append(boards, stored)
+	}
+	return boards, nil
 }
 
-func (s *sudokuBoardFileRepo) loadBoard(n int) ([]byte, int) {
-	data := make([]byte, 41)
-	dataSize := int64(41)
-	offset := dataSize * int64(n)
+func (s *sudokuBoardFileRepo) CountMatching(q Query) (int, error) {
+	if err := s.rlock(); err != nil {
+		return 0, err
+	}
+	defer s.mu.RUnlock()
+	ids, err := s.findIds(q)
+	return len(ids), err
+}
 
-	fStat, err := s.home.Stat()
+func (s *sudokuBoardFileRepo) GetRandomMatching(q Query) (int, *board.SudokuBoard, error) {
+	if err := s.rlock(); err != nil {
+		return 0, nil, err
+	}
+	defer s.mu.RUnlock()
+	stored, err := s.getRandomMatching(q)
+	if err != nil {
+		return 0, nil, err
+	}
+	return stored.Id, stored.Board, nil
+}
+
+func (s *sudokuBoardFileRepo) GetRandomStored(q Query) (StoredBoard, error) {
+	if err := s.rlock(); err != nil {
+		return StoredBoard{}, err
+	}
+	defer s.mu.RUnlock()
+	return s.getRandomMatching(q)
+}
+
+func (s *sudokuBoardFileRepo) getRandomMatching(q Query) (StoredBoard, error) {
+	compiled, err := q.compile()
+	if err != nil {
+		return StoredBoard{}, err
+	}
+	if compiled.all() {
+		return s.getRandom()
+	}
+	ids, err := s.findIds(q)
+	if err != nil {
+		return StoredBoard{}, err
+	}
+	// a board removed since the index was built is dropped and another one picked
+	for len(ids) > 0 {
+		i := rand.Intn(len(ids))
+		stored, err := s.getStored(ids[i])
+		if !errors.Is(err, ErrNotFound) {
+			return stored, err
+		}
+		ids[i] = ids[len(ids)-1]
+		ids = ids[:len(ids)-1]
+	}
+	return StoredBoard{}, fmt.Errorf("no board matches the query: %w", ErrNotFound)
+}
+
+func (s *sudokuBoardFileRepo) findIds(q Query) ([]int, error) {
+	compiled, err := q.compile()
+	if err != nil {
+		return nil, err
+	}
+	index, err := s.loadIndex()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	return index.find(compiled), nil
+}
 
-	if fStat.Size() <= offset {
-		return data, 0 // there is no such index
+// loadIndex builds the index from the metadata of every record the first time it is needed, SaveAllFrom keeps it up
+// to date after that
+func (s *sudokuBoardFileRepo) loadIndex() (*boardIndex, error) {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+	if s.index != nil {
+		return s.index, nil
 	}
-	_, err = s.home.ReadAt(data, offset)
+	index := newBoardIndex()
+	err := s.eachRecord(0, func(_ int, record []byte) bool {
+		index.add(indexEntryOf(record[:boardMetadataBytes]))
+		return true
+	})
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	s.index = index
+	return index, nil
+}
 
-	return data, n
+func (s *sudokuBoardFileRepo) GetRandom() (int, *board.SudokuBoard, error) {
+	if err := s.rlock(); err != nil {
+		return 0, nil, err
+	}
+	defer s.mu.RUnlock()
+	stored, err := s.getRandom()
+	if err != nil {
+		return 0, nil, err
+	}
+	return stored.Id, stored.Board, nil
 }
 
-func (s *sudokuBoardFileRepo) saveToEnd(data []byte) {
-	fStat, err := s.home.Stat()
+func (s *sudokuBoardFileRepo) getRandom() (StoredBoard, error) {
+	count := s.home.count()
+	if count == 0 {
+		return StoredBoard{}, fmt.Errorf("the repository is empty: %w", ErrNotFound)
+	}
+	stored, err := s.getStored(rand.Intn(count))
+	if !errors.Is(err, ErrNotFound) {
+		return stored, err
+	}
+	// the board was removed, pick one of those left instead
+	ids, err := s.findIds(Query{})
 	if err != nil {
-		panic(err)
+		return StoredBoard{}, err
+	}
+	if len(ids) == 0 {
+		return StoredBoard{}, fmt.Errorf("every board was removed: %w", ErrNotFound)
+	}
+	return s.getStored(ids[rand.Intn(len(ids))])
+}
+
+func (s *sudokuBoardFileRepo) GetByNumber(n int) (int, *board.SudokuBoard, error) {
+	if err := s.rlock(); err != nil {
+		return 0, nil, err
 	}
+	defer s.mu.RUnlock()
+	return s.getByNumber(n)
+}
 
-	_, err = s.home.WriteAt(data, fStat.Size())
+func (s *sudokuBoardFileRepo) getByNumber(n int) (int, *board.SudokuBoard, error) {
+	loadedBoard, err := s.loadBoard(n, boardMetadataBytes, boardDataBytes)
 	if err != nil {
-		panic(err)
+		return 0, nil, err
 	}
+	b, err := s.dataToBoard(n, loadedBoard)
+	if err != nil {
+		return 0, nil, err
+	}
+	return n, b, nil
 }
 
-func (s *sudokuBoardFileRepo) boardToData(b *board.SudokuBoard) []byte {
-	// 9x9 is 81 bytes, to make it divisible by 2 we add 1 -> 41
-	result := make([]byte, 0, boardDataBytes)
+func (s *sudokuBoardFileRepo) GetStored(n int) (StoredBoard, error) {
+	if err := s.rlock(); err != nil {
+		return StoredBoard{}, err
+	}
+	defer s.mu.RUnlock()
+	return s.getStored(n)
+}
 
-	i, j := 0, 0
-	var currentByte byte
-	var high bool
-	for j < 9 {
-		if !high {
-			currentByte = lowMask
-			currentByte &= byte(b.GetAt(i, j))
-			high = true
-		} else {
-			currentByte |= highMask & (byte(b.GetAt(i, j)) << 4)
-			result = append(result, currentByte)
-			high = false
+func (s *sudokuBoardFileRepo) getStored(n int) (StoredBoard, error) {
+	record, err := s.loadBoard(n, 0, boardRecordBytes)
+	if err != nil {
+		return StoredBoard{}, err
+	}
+	return s.recordToStored(n, record)
+}
+
+func (s *sudokuBoardFileRepo) GetMetadata(n int) (int, Metadata, error) {
+	if err := s.rlock(); err != nil {
+		return 0, Metadata{}, err
+	}
+	defer s.mu.RUnlock()
+	loadedMetadata, err := s.loadBoard(n, 0, boardMetadataBytes)
+	if err != nil {
+		return 0, Metadata{}, err
+	}
+	return n, decodeMetadata(loadedMetadata), nil
+}
+
+func (s *sudokuBoardFileRepo) GetSolution(n int) (int, *board.SudokuBoard, error) {
+	if err := s.rlock(); err != nil {
+		return 0, nil, err
+	}
+	defer s.mu.RUnlock()
+	loadedSolution, err := s.loadBoard(n, boardMetadataBytes+boardDataBytes, boardDataBytes)
+	if err != nil {
+		return 0, nil, err
+	}
+	b, err := s.dataToBoard(n, loadedSolution)
+	if err != nil {
+		return 0, nil, err
+	}
+	return n, b, nil
+}
+
+func (s *sudokuBoardFileRepo) SaveNew(sudokuBoard *board.SudokuBoard) (int, error) {
+	return s.saveAllFrom("", []*board.SudokuBoard{sudokuBoard})
+}
+
+func (s *sudokuBoardFileRepo) SaveAll(sudokuBoards []*board.SudokuBoard) error {
+	_, err := s.saveAllFrom("", sudokuBoards)
+	return err
+}
+
+func (s *sudokuBoardFileRepo) SaveAllFrom(source string, sudokuBoards []*board.SudokuBoard) error {
+	_, err := s.saveAllFrom(source, sudokuBoards)
+	return err
+}
+
+/*
+saveAllFrom saves the boards after the last one and returns the id of the first. The boards are described before the
+repository is locked as solving and rating them takes far longer than writing them.
+*/
+func (s *sudokuBoardFileRepo) saveAllFrom(source string, sudokuBoards []*board.SudokuBoard) (int, error) {
+	if s.readOnly() {
+		return 0, ErrReadOnly
+	}
+
+	created := s.now()
+	data := make([]byte, 0, boardRecordBytes*len(sudokuBoards))
+	for _, b := range sudokuBoards {
+		metadata, solution := describe(b, source, created)
+		record, err := s.recordToData(metadata, b, solution)
+		if err != nil {
+			return 0, err
+		}
+		data = append(data, record...)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var firstId int
+	err := s.locked(func() error {
+		if err := s.catchUp(); err != nil {
+			return err
+		}
+		firstId = s.home.count()
+		writes, header, err := s.home.appendWrites(data)
+		if err != nil {
+			return err
+		}
+		constraintWrite, encoded, err := s.constraintWrite(firstId, sudokuBoards)
+		if err != nil {
+			return err
+		}
+		if len(constraintWrite.data) > 0 {
+			writes = append(writes, constraintWrite)
+		}
+		if err := s.journal.commit(writes); err != nil {
+			return err
 		}
 
-		i++
-		if i >= 9 {
-			j++
-			i = 0
+		s.home.header = header
+		for id, e := range encoded {
+			s.constraintsById[id] = e
 		}
+		if s.index != nil {
+			for record := data; len(record) > 0; record = record[boardRecordBytes:] {
+				s.index.add(indexEntryOf(record[:boardMetadataBytes]))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
-	result = append(result, currentByte)
+	return firstId, nil
+}
 
-	return result
+// constraintWrite returns the write appending the constraints of the boards saved from firstId on, and them by id
+func (s *sudokuBoardFileRepo) constraintWrite(firstId int,
+	sudokuBoards []*board.SudokuBoard) (journalWrite, map[int]string, error) {
+	encoded := map[int]string{}
+	var lines []byte
+	for i, b := range sudokuBoards {
+		if len(b.Constraints()) > 0 {
+			encoded[firstId+i] = board.FormatConstraints(b.Constraints())
+			lines = fmt.Appendf(lines, "%d %s\n", firstId+i, encoded[firstId+i])
+		}
+	}
+	fStat, err := s.constraints.Stat()
+	if err != nil {
+		return journalWrite{}, nil, err
+	}
+	return journalWrite{name: dbConstraintFile, offset: fStat.Size(), data: lines}, encoded, nil
 }
 
-func (s *sudokuBoardFileRepo) dataToBoard(data []byte) *board.SudokuBoard {
-	b := board.SudokuBoard{}
-	for j := 0; j < 9; j++ {
-		for i := 0; i < 9; i++ {
-			index := i + (j * 9)
-			// even index -> high byte
-			if index%2 == 0 {
-				b.SetAt(i, j, int(data[index/2]&lowMask))
-			} else {
-				b.SetAt(i, j, int(data[index/2]&highMask>>4))
+func (s *sudokuBoardFileRepo) Update(id int, sudokuBoard *board.SudokuBoard) error {
+	if s.readOnly() {
+		return ErrReadOnly
+	}
+	metadata, solution := describe(sudokuBoard, "", time.Time{})
+	return s.rewrite(id, func(old Metadata) ([]journalWrite, error) {
+		metadata.Source, metadata.Created = old.Source, old.Created
+		record, err := s.recordToData(metadata, sudokuBoard, solution)
+		if err != nil {
+			return nil, err
+		}
+		writes := []journalWrite{s.home.recordWrite(id, record)}
+
+		if _, had := s.constraintsById[id]; had || len(sudokuBoard.Constraints()) > 0 {
+			fStat, err := s.constraints.Stat()
+			if err != nil {
+				return nil, err
 			}
+			line := fmt.Appendf(nil, "%d %s\n", id, board.FormatConstraints(sudokuBoard.Constraints()))
+			writes = append(writes, journalWrite{name: dbConstraintFile, offset: fStat.Size(), data: line})
+		}
+		return writes, nil
+	})
+}
+
+func (s *sudokuBoardFileRepo) Delete(id int) error {
+	if s.readOnly() {
+		return ErrReadOnly
+	}
+	return s.rewrite(id, func(Metadata) ([]journalWrite, error) {
+		return []journalWrite{s.home.recordWrite(id, removedRecord())}, nil
+	})
+}
+
+/*
+rewrite commits the writes change returns for board id, which gets the metadata the board has. It holds mu and the
+file lock from before the board is read until the writes are made, so nothing changes the board in between. Boards
+that were removed can not be changed.
+*/
+func (s *sudokuBoardFileRepo) rewrite(id int, change func(old Metadata) ([]journalWrite, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.locked(func() error {
+		if err := s.catchUp(); err != nil {
+			return err
+		}
+		data, err := s.loadBoard(id, 0, boardMetadataBytes)
+		if err != nil {
+			return err
+		}
+		writes, err := change(decodeMetadata(data))
+		if err != nil {
+			return err
+		}
+		generationWrite, header := s.home.generationWrite()
+		if err := s.journal.commit(append(writes, generationWrite)); err != nil {
+			return err
+		}
+		s.home.header = header
+
+		// the index has the board under its old metadata
+		s.index = nil
+		for _, w := range writes {
+			if w.name == dbConstraintFile {
+				return s.loadConstraints()
+			}
+		}
+		return nil
+	})
+}
+
+// loadConstraints reads the whole constraint file, it is read again when other processes saved boards
+func (s *sudokuBoardFileRepo) loadConstraints() error {
+	s.constraintsById = map[int]string{}
+	if _, err := s.constraints.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(s.constraints)
+	for line := 1; scanner.Scan(); line++ {
+		idText, encoded, found := strings.Cut(scanner.Text(), " ")
+		if !found {
+			continue
+		}
+		id, err := strconv.Atoi(idText)
+		if err != nil {
+			return fmt.Errorf("%s line %d: %w: %v", dbConstraintFile, line, ErrCorrupt, err)
+		}
+		if encoded == "" {
+			delete(s.constraintsById, id) // the board was updated to one without constraints
+		} else {
+			s.constraintsById[id] = encoded
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *sudokuBoardFileRepo) withConstraints(id int, b *board.SudokuBoard) (*board.SudokuBoard, error) {
+	encoded, found := s.constraintsById[id]
+	if !found {
+		return b, nil
+	}
+	constraints, err := board.ParseConstraints(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("constraints of board %d: %w: %v", id, ErrCorrupt, err)
+	}
+	return b.AddConstraints(constraints...), nil
+}
+
+/*
+truncateConstraints removes what a save that did not finish left at the end of the constraint file, a line cut short
+and the lines of boards from count on, as the board file was not written. Such lines are the last ones so everything
+from the first of them on is removed. A read only file is left as it is.
+*/
+func (s *sudokuBoardFileRepo) truncateConstraints(count int) error {
+	if s.constraintsReadOnly {
+		return nil
+	}
+	if _, err := s.constraints.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	content, err := io.ReadAll(s.constraints)
+	if err != nil {
+		return err
+	}
+	end := 0
+	for end < len(content) {
+		line, _, found := bytes.Cut(content[end:], []byte("\n"))
+		if !found {
+			break
+		}
+		idText, _, _ := bytes.Cut(line, []byte(" "))
+		if id, err := strconv.Atoi(string(idText)); err == nil && id >= count {
+			break
+		}
+		end += len(line) + 1
+	}
+	if end == len(content) {
+		return nil
+	}
+	if err := s.constraints.Truncate(int64(end)); err != nil {
+		return err
+	}
+	return s.loadConstraints()
+}
+
+// loadBoard returns size bytes of record n from start, see boardFormat for the fields, once the record is checked
+func (s *sudokuBoardFileRepo) loadBoard(n, start, size int) ([]byte, error) {
+	if n < 0 || n >= s.home.count() {
+		return nil, fmt.Errorf("board %d: %w", n, ErrNotFound)
+	}
+	record, err := s.home.read(n)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRecord(n, record); err != nil {
+		return nil, err
+	}
+	if isRemoved(record) {
+		return nil, fmt.Errorf("board %d was removed: %w", n, ErrNotFound)
+	}
+	return record[start : start+size], nil
+}
+
+/*
+upgradeRecord turns a version 1 record, which only held the numbers of the board, into a record of the current
+version. The creation time and source were not kept so they stay unknown. A record that is not a valid board is kept
+as it is with blank metadata.
+*/
+func (s *sudokuBoardFileRepo) upgradeRecord(n int, record []byte) []byte {
+	upgraded := make([]byte, boardRecordBytes-boardChecksumBytes)
+	copy(upgraded[boardMetadataBytes:], record)
+
+	b := &board.SudokuBoard{}
+	if err := b.UnmarshalBinary(record); err != nil {
+		return sealRecord(upgraded)
+	}
+	constrained, err := s.withConstraints(n, b)
+	if err != nil {
+		return sealRecord(upgraded)
+	}
+	metadata, solution := describe(constrained, "", time.Time{})
+	data, err := s.recordToData(metadata, b, solution)
+	if err != nil {
+		return sealRecord(upgraded)
+	}
+	return data
+}
+
+func (s *sudokuBoardFileRepo) recordToData(metadata Metadata, b, solution *board.SudokuBoard) ([]byte, error) {
+	data, err := encodeMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+	for _, grid := range []*board.SudokuBoard{b, solution} {
+		encoded, err := grid.MarshalBinary()
+		if err != nil {
+			return nil, err
 		}
+		// constraints are kept in the constraint file, the record only holds the numbers
+		data = append(data, encoded[:boardDataBytes]...)
+	}
+	return sealRecord(data), nil
+}
+
+// recordToStored checks and decodes the whole record of board id
+func (s *sudokuBoardFileRepo) recordToStored(id int, record []byte) (StoredBoard, error) {
+	if err := checkRecord(id, record); err != nil {
+		return StoredBoard{}, err
+	}
+	b, err := s.dataToBoard(id, record[boardMetadataBytes:boardMetadataBytes+boardDataBytes])
+	if err != nil {
+		return StoredBoard{}, err
+	}
+	return StoredBoard{Id: id, Board: b, Metadata: decodeMetadata(record[:boardMetadataBytes])}, nil
+}
+
+// dataToBoard decodes the numbers of board id and adds its constraints
+func (s *sudokuBoardFileRepo) dataToBoard(id int, data []byte) (*board.SudokuBoard, error) {
+	b := &board.SudokuBoard{}
+	if err := b.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("board %d: %w: %v", id, ErrCorrupt, err)
 	}
-	return &b
+	return s.withConstraints(id, b)
 }
@@ -1,10 +1,14 @@
 package repository
 
 import (
+	"errors"
 	"reflect"
+	"strconv"
 	"testing"
+	"time"
 
 	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/solver"
 	"github.com/spf13/afero"
 )
 
@@ -78,15 +82,11 @@ func Test_sudokuBoardFileRepo_GetRandomBoard(t *testing.T) {
 			name: "Skip board",
 			args: args{
 				initialFileContent: []byte{
-					0, 0, 0, 0, 0, 0, 0, 0, 0,
-					0, 0, 0, 0, 0, 0, 0, 0, 0,
-					0, 0, 0, 0, 0, 0, 0, 0, 0,
-					0, 0, 0, 0, 0, 0, 0, 0, 0,
-					0, 0, 0, 0, 0, 0, 0, 0, 0,
-					0, 0, 0, 0, 0, 0, 0, 0, 0,
-					0, 0, 0, 0, 0, 0, 0, 0, 0,
-					0, 0, 0, 0, 0, 0, 0, 0, 0,
-					0, 0, 0, 0, 0, 0, 0, 0, 0,
+					0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+					0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+					0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+					0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+					0x00,
 					0x21, 0x43, 0x65, 0x87, 0x19, 0x32, 0x54, 0x76, 0x98, 0x21,
 					0x43, 0x65, 0x87, 0x19, 0x32, 0x54, 0x76, 0x98, 0x21, 0x43,
 					0x65, 0x87, 0x19, 0x32, 0x54, 0x76, 0x98, 0x21, 0x43, 0x65,
@@ -132,9 +132,15 @@ func Test_sudokuBoardFileRepo_GetRandomBoard(t *testing.T) {
 			_ = file.Sync()
 			_ = file.Close()
 
-			s, sd := NewSudokuBoardRepoUsingFs(fs)
-			_, _ = s.GetRandom()
-			sd()
+			s, sd, err := NewSudokuBoardRepoUsingFs(fs)
+			if err != nil {
+				t.Fatalf("NewSudokuBoardRepoUsingFs() error = %v", err)
+			}
+			// only an empty repository has no board to return
+			if _, _, err := s.GetRandom(); err != nil && !errors.Is(err, ErrNotFound) {
+				t.Errorf("GetRandom() error = %v", err)
+			}
+			_ = sd()
 		})
 	}
 }
@@ -146,6 +152,7 @@ func Test_sudokuBoardFileRepo_GetBoardNumber(t *testing.T) {
 	}
 	type want struct {
 		sudokuBoard *board.SudokuBoard
+		err         error
 	}
 	tests := []struct {
 		name string
@@ -175,7 +182,7 @@ func Test_sudokuBoardFileRepo_GetBoardNumber(t *testing.T) {
 				initialFileContent: []byte{},
 			},
 			want: want{
-				sudokuBoard: board.FromNumbers([9][9]int{}),
+				err: ErrNotFound,
 			},
 		},
 		{
@@ -191,7 +198,7 @@ func Test_sudokuBoardFileRepo_GetBoardNumber(t *testing.T) {
 				},
 			},
 			want: want{
-				sudokuBoard: board.FromNumbers([9][9]int{}),
+				err: ErrNotFound,
 			},
 		},
 		{
@@ -205,7 +212,7 @@ func Test_sudokuBoardFileRepo_GetBoardNumber(t *testing.T) {
 				},
 			},
 			want: want{
-				sudokuBoard: board.FromNumbers([9][9]int{}),
+				err: ErrNotFound,
 			},
 		},
 		{
@@ -316,10 +323,16 @@ func Test_sudokuBoardFileRepo_GetBoardNumber(t *testing.T) {
 			_ = file.Sync()
 			_ = file.Close()
 
-			s, sd := NewSudokuBoardRepoUsingFs(fs)
-			_, b := s.GetByNumber(tt.args.boardNumber)
-			sd()
+			s, sd, err := NewSudokuBoardRepoUsingFs(fs)
+			if err != nil {
+				t.Fatalf("NewSudokuBoardRepoUsingFs() error = %v", err)
+			}
+			_, b, err := s.GetByNumber(tt.args.boardNumber)
+			_ = sd()
 
+			if !errors.Is(err, tt.want.err) {
+				t.Errorf("sudokuBoardFileRepo.GetByNumber() error = %v, want %v", err, tt.want.err)
+			}
 			if !reflect.DeepEqual(b, tt.want.sudokuBoard) {
 				t.Errorf("sudokuBoardFileRepo.SaveNew() = \n%v, want \n%v", b, tt.want.sudokuBoard)
 			}
@@ -358,11 +371,21 @@ func Test_sudokuBoardFileRepo_SaveNewBoard(t *testing.T) {
 		{
 			name: "Always write to end of file",
 			args: args{
-				sudokuBoard:        board.FromNumbers([9][9]int{}),
-				initialFileContent: []byte{255},
+				sudokuBoard: board.FromNumbers([9][9]int{}),
+				initialFileContent: []byte{
+					0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+					0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+					0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+					0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+					0xFF,
+				},
 			},
 			want: want{
 				expectedContent: []byte{
+					0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+					0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+					0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+					0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
 					0xFF,
 					0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 					0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
@@ -412,15 +435,527 @@ func Test_sudokuBoardFileRepo_SaveNewBoard(t *testing.T) {
 			_ = file.Sync()
 			_ = file.Close()
 
-			s, sd := NewSudokuBoardRepoUsingFs(fs)
-			s.SaveNew(tt.args.sudokuBoard)
-			sd()
+			s, sd, err := NewSudokuBoardRepoUsingFs(fs)
+			if err != nil {
+				t.Fatalf("NewSudokuBoardRepoUsingFs() error = %v", err)
+			}
+			if _, err := s.SaveNew(tt.args.sudokuBoard); err != nil {
+				t.Errorf("sudokuBoardFileRepo.SaveNew() error = %v", err)
+			}
+			_ = sd()
 
+			// the records follow the header, the board of each record follows its metadata
 			file, _ = fs.Open(dbBoardFile)
 			content, _ := afero.ReadAll(file)
+			content = recordFields(content[recordHeaderBytes:], boardMetadataBytes, boardDataBytes)
 			if !reflect.DeepEqual(content, tt.want.expectedContent) {
 				t.Errorf("sudokuBoardFileRepo.SaveNew() = \n%v, want \n%v", content, tt.want.expectedContent)
 			}
 		})
 	}
 }
+
+// recordFields joins the size bytes from start of every record of the board file
+func recordFields(records []byte, start, size int) []byte {
+	var fields []byte
+	for len(records) >= boardRecordBytes {
+		fields = append(fields, records[start:start+size]...)
+		records = records[boardRecordBytes:]
+	}
+	return fields
+}
+
+func Test_sudokuBoardFileRepo_Constraints(t *testing.T) {
+	plain := board.FromNumbers([9][9]int{{1, 2, 3}})
+	variant := board.FromNumbers([9][9]int{{4, 5, 6}}).AddConstraints(
+		board.AntiKnightConstraint{},
+		board.ThermometerConstraint{Cells: []board.Cell{{X: 0, Y: 0}, {X: 1, Y: 0}}},
+		board.SandwichConstraint{Column: true, Index: 4, Sum: 12},
+	)
+
+	fs := afero.NewMemMapFs()
+	s, sd, _ := NewSudokuBoardRepoUsingFs(fs)
+	_ = s.SaveAll([]*board.SudokuBoard{plain, variant})
+	if id, err := s.SaveNew(plain); id != 2 || err != nil {
+		t.Errorf("SaveNew() = %d, %v, want 2", id, err)
+	}
+	_ = sd()
+
+	// reopen to make sure the constraints are read back from the file
+	s, sd, _ = NewSudokuBoardRepoUsingFs(fs)
+	defer sd()
+	for id, want := range []*board.SudokuBoard{plain, variant, plain} {
+		if _, got, _ := s.GetByNumber(id); !reflect.DeepEqual(got, want) {
+			t.Errorf("GetByNumber(%d) = \n%v %v, want \n%v %v", id, got, got.Constraints(), want, want.Constraints())
+		}
+	}
+
+	file, _ := fs.Open(dbConstraintFile)
+	content, _ := afero.ReadAll(file)
+	want := "1 antiknight thermo:r1c1,r1c2 sandwich:col5=12\n"
+	if string(content) != want {
+		t.Errorf("constraint file = %q, want %q", content, want)
+	}
+}
+
+func Test_sudokuBoardFileRepo_Metadata(t *testing.T) {
+	puzzle, _ := board.ParseLine(
+		"53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79")
+	solution, _ := board.ParseLine(
+		"534678912672195348198342567859761423426853791713924856961537284287419635345286179")
+	created := time.Date(2024, 5, 17, 8, 30, 0, 0, time.UTC)
+
+	fs := afero.NewMemMapFs()
+	s, sd, _ := NewSudokuBoardRepoUsingFs(fs)
+	s.(*sudokuBoardFileRepo).now = func() time.Time { return created }
+	if err := s.SaveAllFrom("nyt/easy", []*board.SudokuBoard{puzzle, board.FromNumbers([9][9]int{})}); err != nil {
+		t.Errorf("SaveAllFrom() error = %v", err)
+	}
+	_ = sd()
+
+	s, sd, _ = NewSudokuBoardRepoUsingFs(fs)
+	defer sd()
+	want, _ := describe(puzzle, "nyt/easy", created)
+	if id, got, err := s.GetMetadata(0); id != 0 || err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMetadata(0) = %d %+v, %v, want 0 %+v", id, got, err, want)
+	}
+	if id, got, err := s.GetSolution(0); id != 0 || err != nil || !reflect.DeepEqual(got, solution) {
+		t.Errorf("GetSolution(0) = %d \n%v, %v, want 0 \n%v", id, got, err, solution)
+	}
+
+	if _, got, _ := s.GetMetadata(1); got.Unique || got.Source != "nyt/easy" || got.Created != created {
+		t.Errorf("GetMetadata(1) = %+v, want a board without a single solution", got)
+	}
+	if _, got, _ := s.GetSolution(1); !reflect.DeepEqual(got, board.FromNumbers([9][9]int{})) {
+		t.Errorf("GetSolution(1) = \n%v, want a blank board", got)
+	}
+	if _, _, err := s.GetMetadata(2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetMetadata(2) error = %v, want %v", err, ErrNotFound)
+	}
+	if _, _, err := s.GetSolution(-1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetSolution(-1) error = %v, want %v", err, ErrNotFound)
+	}
+
+	wantStored := StoredBoard{Id: 0, Board: puzzle, Metadata: want}
+	if got, err := s.GetStored(0); err != nil || !reflect.DeepEqual(got, wantStored) {
+		t.Errorf("GetStored(0) = %+v, %v, want %+v", got, err, wantStored)
+	}
+	if _, err := s.GetStored(2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetStored(2) error = %v, want %v", err, ErrNotFound)
+	}
+	q := QueryDifficulty(want.Rating.Difficulty)
+	if got, err := s.GetRandomStored(q); err != nil || !reflect.DeepEqual(got, wantStored) {
+		t.Errorf("GetRandomStored() = %+v, %v, want %+v", got, err, wantStored)
+	}
+}
+
+func Test_sudokuBoardFileRepo_Upgrade(t *testing.T) {
+	puzzle, _ := board.ParseLine(
+		"53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79")
+	variant := board.FromNumbers([9][9]int{{1, 2, 3}}).AddConstraints(board.AntiKnightConstraint{})
+
+	// a version 1 file only holds the numbers of each board
+	fs := afero.NewMemMapFs()
+	var records []byte
+	for _, b := range []*board.SudokuBoard{puzzle, variant} {
+		data, _ := b.MarshalBinary()
+		records = append(records, data[:boardDataBytes]...)
+	}
+	v1 := recordFormat{magic: boardFormat.magic, version: 1, recordSize: boardDataBytes}
+	f, _ := openRecordFile(fs, dbBoardFile, v1)
+	_, _ = f.append(records)
+	_ = f.close()
+	_ = afero.WriteFile(fs, dbConstraintFile, []byte("1 antiknight\n"), 0666)
+
+	s, sd, err := NewSudokuBoardRepoUsingFs(fs)
+	if err != nil {
+		t.Fatalf("NewSudokuBoardRepoUsingFs() error = %v", err)
+	}
+	defer sd()
+	for id, b := range []*board.SudokuBoard{puzzle, variant} {
+		wantMetadata, wantSolution := describe(b, "", time.Time{})
+		if _, got, _ := s.GetByNumber(id); !reflect.DeepEqual(got, b) {
+			t.Errorf("GetByNumber(%d) = \n%v, want \n%v", id, got, b)
+		}
+		if _, got, _ := s.GetMetadata(id); !reflect.DeepEqual(got, wantMetadata) {
+			t.Errorf("GetMetadata(%d) = %+v, want %+v", id, got, wantMetadata)
+		}
+		if _, got, _ := s.GetSolution(id); !reflect.DeepEqual(got, wantSolution.AddConstraints(b.Constraints()...)) {
+			t.Errorf("GetSolution(%d) = \n%v, want \n%v", id, got, wantSolution)
+		}
+	}
+}
+
+func Test_sudokuBoardFileRepo_UpgradeChecksum(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s, sd, _ := NewSudokuBoardRepoUsingFs(fs)
+	_ = s.SaveAllFrom("nyt/easy", []*board.SudokuBoard{board.FromNumbers([9][9]int{{1, 2, 3}})})
+	_ = sd()
+
+	// a version 2 record is a version 3 record without the checksum
+	content, _ := afero.ReadFile(fs, dbBoardFile)
+	v2 := recordFormat{magic: boardFormat.magic, version: 2, recordSize: boardRecordBytes - boardChecksumBytes}
+	_ = fs.Remove(dbBoardFile)
+	f, _ := openRecordFile(fs, dbBoardFile, v2)
+	_, _ = f.append(content[recordHeaderBytes : recordHeaderBytes+boardRecordBytes-boardChecksumBytes])
+	_ = f.close()
+
+	s, sd, err := NewSudokuBoardRepoUsingFs(fs)
+	if err != nil {
+		t.Fatalf("NewSudokuBoardRepoUsingFs() error = %v", err)
+	}
+	defer sd()
+	if _, got, err := s.GetMetadata(0); err != nil || got.Source != "nyt/easy" {
+		t.Errorf("GetMetadata(0) = %+v, %v", got, err)
+	}
+	if upgraded, _ := afero.ReadFile(fs, dbBoardFile); !reflect.DeepEqual(upgraded, content) {
+		t.Errorf("upgraded file = \n%v, want \n%v", upgraded, content)
+	}
+}
+
+func Test_sudokuBoardFileRepo_UpdateDelete(t *testing.T) {
+	puzzle, _ := board.ParseLine(
+		"53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79")
+	typo := puzzle.Copy()
+	typo.SetAt(2, 0, 5)
+	variant := board.FromNumbers([9][9]int{{4, 5, 6}}).AddConstraints(board.AntiKnightConstraint{})
+	created := time.Date(2024, 5, 17, 8, 30, 0, 0, time.UTC)
+
+	fs := afero.NewMemMapFs()
+	s, sd, _ := NewSudokuBoardRepoUsingFs(fs)
+	s.(*sudokuBoardFileRepo).now = func() time.Time { return created }
+	_ = s.SaveAllFrom("nyt/easy", []*board.SudokuBoard{typo, variant, puzzle})
+	_, _ = s.Find(Query{}, 0, 3) // builds the index so updates have to keep it right
+
+	if err := s.Update(0, puzzle); err != nil {
+		t.Errorf("Update(0) error = %v", err)
+	}
+	if err := s.Update(1, board.FromNumbers([9][9]int{{4, 5, 6}})); err != nil {
+		t.Errorf("Update(1) error = %v", err)
+	}
+	if err := s.Delete(2); err != nil {
+		t.Errorf("Delete(2) error = %v", err)
+	}
+	if err := s.Delete(2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete(2) of a deleted board error = %v, want %v", err, ErrNotFound)
+	}
+	if err := s.Update(2, puzzle); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Update(2) of a deleted board error = %v, want %v", err, ErrNotFound)
+	}
+	if err := s.Delete(3); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete(3) error = %v, want %v", err, ErrNotFound)
+	}
+	if count, _ := s.CountMatching(QueryDifficulty(solver.StrategyDifficultyEasy)); count != 1 {
+		t.Errorf("CountMatching() of easy boards = %d, want 1", count)
+	}
+	_ = sd()
+
+	// reopen to make sure the changes are read back from the files
+	s, sd, _ = NewSudokuBoardRepoUsingFs(fs)
+	defer sd()
+	want, _ := describe(puzzle, "nyt/easy", created)
+	if _, got, err := s.GetMetadata(0); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMetadata(0) = %+v, %v, want %+v", got, err, want)
+	}
+	for id, b := range []*board.SudokuBoard{puzzle, board.FromNumbers([9][9]int{{4, 5, 6}})} {
+		if _, got, err := s.GetByNumber(id); err != nil || !reflect.DeepEqual(got, b) {
+			t.Errorf("GetByNumber(%d) = \n%v %v, %v, want \n%v", id, got, got.Constraints(), err, b)
+		}
+	}
+	if _, _, err := s.GetByNumber(2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetByNumber(2) error = %v, want %v", err, ErrNotFound)
+	}
+	if count, _ := s.Count(); count != 3 {
+		t.Errorf("Count() = %d, want 3 as deleted boards keep their id", count)
+	}
+
+	readOnly, readOnlySd, _ := NewSudokuBoardRepoUsingFs(afero.NewReadOnlyFs(fs))
+	defer readOnlySd()
+	if err := readOnly.Update(0, typo); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Update() of a read only repository error = %v, want %v", err, ErrReadOnly)
+	}
+	if err := readOnly.Delete(0); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Delete() of a read only repository error = %v, want %v", err, ErrReadOnly)
+	}
+}
+
+func Test_sudokuBoardFileRepo_UpdateDeleteSeenByOthers(t *testing.T) {
+	plain := board.FromNumbers([9][9]int{{1, 2, 3}})
+	variant := plain.Copy().AddConstraints(board.AntiKingConstraint{})
+	fs := afero.NewMemMapFs()
+	writer, writerSd, _ := NewSudokuBoardRepoUsingFs(fs)
+	defer writerSd()
+	_ = writer.SaveAll([]*board.SudokuBoard{plain, board.FromNumbers([9][9]int{{4, 5, 6}})})
+
+	// the reader has read the repository before the writer changes it, the number of boards stays the same
+	reader, readerSd, _ := NewSudokuBoardRepoUsingFs(fs)
+	defer readerSd()
+	_, _ = reader.Find(Query{}, 0, 2)
+	_ = writer.Update(0, variant)
+	_ = writer.Delete(1)
+
+	if _, got, err := reader.GetByNumber(0); err != nil || !reflect.DeepEqual(got, variant) {
+		t.Errorf("GetByNumber(0) = \n%v %v, %v, want \n%v %v", got, got.Constraints(), err, variant, variant.Constraints())
+	}
+	if _, _, err := reader.GetByNumber(1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetByNumber(1) error = %v, want %v", err, ErrNotFound)
+	}
+	if list, _ := reader.Find(Query{}, 0, 2); len(list) != 1 || list[0].Id != 0 {
+		t.Errorf("Find() = %+v, want board 0", list)
+	}
+}
+
+func Test_sudokuBoardFileRepo_StaleIndex(t *testing.T) {
+	s, sd, _ := NewSudokuBoardRepoUsingFs(afero.NewMemMapFs())
+	defer sd()
+	_ = s.SaveAll([]*board.SudokuBoard{board.FromNumbers([9][9]int{{1, 2, 3}}), board.FromNumbers([9][9]int{{4, 5, 6}})})
+	repo := s.(*sudokuBoardFileRepo)
+	index, _ := repo.loadIndex()
+	_ = s.Delete(1)
+	repo.index = index // an index that still has the removed board
+
+	if list, err := s.Find(Query{}, 0, 2); err != nil || len(list) != 1 || list[0].Id != 0 {
+		t.Errorf("Find() = %+v, %v, want board 0", list, err)
+	}
+	q := Query{MaxClues: 3}
+	for i := 0; i < 20; i++ {
+		if id, _, err := s.GetRandomMatching(q); err != nil || id != 0 {
+			t.Fatalf("GetRandomMatching() = %d, %v, want board 0", id, err)
+		}
+	}
+}
+
+func Test_sudokuBoardFileRepo_ReadOnly(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s, sd, _ := NewSudokuBoardRepoUsingFs(fs)
+	_, _ = s.SaveNew(board.FromNumbers([9][9]int{{1, 2, 3}}))
+	_ = sd()
+
+	s, sd, err := NewSudokuBoardRepoUsingFs(afero.NewReadOnlyFs(fs))
+	if err != nil {
+		t.Fatalf("NewSudokuBoardRepoUsingFs() error = %v", err)
+	}
+	if _, got, err := s.GetByNumber(0); err != nil || !reflect.DeepEqual(got, board.FromNumbers([9][9]int{{1, 2, 3}})) {
+		t.Errorf("GetByNumber(0) = \n%v, %v", got, err)
+	}
+	if _, err := s.SaveNew(board.FromNumbers([9][9]int{})); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("SaveNew() error = %v, want %v", err, ErrReadOnly)
+	}
+	if err := sd(); err != nil {
+		t.Errorf("shutdown error = %v", err)
+	}
+
+	// a file without a header can not be migrated
+	raw := afero.NewMemMapFs()
+	_ = afero.WriteFile(raw, dbBoardFile, make([]byte, boardDataBytes), 0666)
+	_ = afero.WriteFile(raw, dbConstraintFile, nil, 0666)
+	if _, _, err := NewSudokuBoardRepoUsingFs(afero.NewReadOnlyFs(raw)); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("NewSudokuBoardRepoUsingFs() of a raw file error = %v, want %v", err, ErrReadOnly)
+	}
+}
+
+func Test_sudokuBoardFileRepo_Corrupt(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(fs afero.Fs)
+		open    bool
+	}{
+		{
+			name: "record",
+			corrupt: func(fs afero.Fs) {
+				content, _ := afero.ReadFile(fs, dbBoardFile)
+				content[recordHeaderBytes+boardMetadataBytes+boardDataBytes-1] = 0xF0
+				_ = afero.WriteFile(fs, dbBoardFile, content, 0666)
+			},
+			open: true,
+		},
+		{
+			name: "constraints",
+			corrupt: func(fs afero.Fs) {
+				_ = afero.WriteFile(fs, dbConstraintFile, []byte("0 thermo:r0c0\n"), 0666)
+			},
+			open: true,
+		},
+		{
+			name: "constraint id",
+			corrupt: func(fs afero.Fs) {
+				_ = afero.WriteFile(fs, dbConstraintFile, []byte("first antiknight\n"), 0666)
+			},
+		},
+		{
+			name: "truncated",
+			corrupt: func(fs afero.Fs) {
+				content, _ := afero.ReadFile(fs, dbBoardFile)
+				_ = afero.WriteFile(fs, dbBoardFile, content[:len(content)-1], 0666)
+			},
+		},
+		{
+			name: "header",
+			corrupt: func(fs afero.Fs) {
+				content, _ := afero.ReadFile(fs, dbBoardFile)
+				_ = afero.WriteFile(fs, dbBoardFile, content[:recordHeaderBytes-1], 0666)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			s, sd, _ := NewSudokuBoardRepoUsingFs(fs)
+			_, _ = s.SaveNew(board.FromNumbers([9][9]int{}))
+			_ = sd()
+			tt.corrupt(fs)
+
+			s, sd, err := NewSudokuBoardRepoUsingFs(fs)
+			if !tt.open {
+				if !errors.Is(err, ErrCorrupt) {
+					t.Errorf("NewSudokuBoardRepoUsingFs() error = %v, want %v", err, ErrCorrupt)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewSudokuBoardRepoUsingFs() error = %v", err)
+			}
+			defer sd()
+			if _, _, err := s.GetByNumber(0); !errors.Is(err, ErrCorrupt) {
+				t.Errorf("GetByNumber() error = %v, want %v", err, ErrCorrupt)
+			}
+		})
+	}
+}
+
+func Test_sudokuBoardFileRepo_GetRandomReachesAll(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s, sd, _ := NewSudokuBoardRepoUsingFs(fs)
+	defer sd()
+	var boards []*board.SudokuBoard
+	for v := 1; v <= 5; v++ {
+		boards = append(boards, board.FromNumbers([9][9]int{{v}}))
+	}
+	_ = s.SaveAll(boards)
+
+	seen := map[int]bool{}
+	for i := 0; i < 500 && len(seen) < len(boards); i++ {
+		id, got, err := s.GetRandom()
+		if err != nil || !reflect.DeepEqual(got, boards[id]) {
+			t.Fatalf("GetRandom() = %d \n%v, %v", id, got, err)
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(boards) {
+		t.Errorf("GetRandom() only returned %v of %d boards", seen, len(boards))
+	}
+}
+
+func Test_sudokuBoardFileRepo_List(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s, sd, _ := NewSudokuBoardRepoUsingFs(fs)
+	defer sd()
+
+	// enough boards to take more than one chunk, written directly as describing each would be slow
+	repo := s.(*sudokuBoardFileRepo)
+	count := eachChunkRecords + 10
+	var records []byte
+	for id := 0; id < count; id++ {
+		b := board.FromNumbers([9][9]int{{id%9 + 1}, {id/9%9 + 1}})
+		record, _ := repo.recordToData(Metadata{Clues: 2, Source: strconv.Itoa(id)}, b, board.FromNumbers([9][9]int{}))
+		records = append(records, record...)
+	}
+	_, _ = repo.home.append(records)
+	if got, err := s.Count(); got != count || err != nil {
+		t.Fatalf("Count() = %d, %v, want %d", got, err, count)
+	}
+
+	tests := []struct {
+		name    string
+		offset  int
+		limit   int
+		wantIds []int
+		wantErr error
+	}{
+		{name: "first page", offset: 0, limit: 3, wantIds: []int{0, 1, 2}},
+		{
+			name:    "across chunks",
+			offset:  eachChunkRecords - 1,
+			limit:   2,
+			wantIds: []int{eachChunkRecords - 1, eachChunkRecords},
+		},
+		{name: "last page", offset: count - 2, limit: 5, wantIds: []int{count - 2, count - 1}},
+		{name: "beyond the end", offset: count, limit: 5, wantIds: []int{}},
+		{name: "no limit", offset: 4, limit: 0, wantIds: []int{}},
+		{name: "negative offset", offset: -1, limit: 5, wantIds: []int{}, wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.List(tt.offset, tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("List() error = %v, want %v", err, tt.wantErr)
+			}
+			ids := []int{}
+			for _, stored := range got {
+				ids = append(ids, stored.Id)
+				_, want, _ := s.GetByNumber(stored.Id)
+				if !reflect.DeepEqual(stored.Board, want) || stored.Metadata.Source != strconv.Itoa(stored.Id) {
+					t.Errorf("List() board %d = \n%v %+v, want \n%v", stored.Id, stored.Board, stored.Metadata, want)
+				}
+			}
+			if !reflect.DeepEqual(ids, tt.wantIds) {
+				t.Errorf("List() ids = %v, want %v", ids, tt.wantIds)
+			}
+		})
+	}
+
+	visited := 0
+	err := s.Each(0, func(stored StoredBoard) bool {
+		if stored.Id != visited {
+			t.Errorf("Each() visited %d, want %d", stored.Id, visited)
+		}
+		visited++
+		return true
+	})
+	if err != nil || visited != count {
+		t.Errorf("Each() visited %d boards, %v, want %d", visited, err, count)
+	}
+}
+
+func Test_sudokuBoardFileRepo_Query(t *testing.T) {
+	easy, _ := board.ParseLine(
+		"53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79")
+	solution, _ := board.ParseLine(
+		"534678912672195348198342567859761423426853791713924856961537284287419635345286179")
+
+	fs := afero.NewMemMapFs()
+	s, sd, _ := NewSudokuBoardRepoUsingFs(fs)
+	defer sd()
+	_ = s.SaveAll([]*board.SudokuBoard{board.FromNumbers([9][9]int{}), easy, solution})
+
+	easyQuery := QueryDifficulty(solver.StrategyDifficultyEasy)
+	if n, err := s.CountMatching(easyQuery); n != 2 || err != nil {
+		t.Errorf("CountMatching() = %d, %v, want 2", n, err)
+	}
+	for i := 0; i < 20; i++ {
+		if id, _, err := s.GetRandomMatching(easyQuery); (id != 1 && id != 2) || err != nil {
+			t.Errorf("GetRandomMatching() = %d, %v, want 1 or 2", id, err)
+		}
+	}
+	found, err := s.Find(Query{MinClues: 1, MaxClues: 80}, 0, 10)
+	if err != nil || len(found) != 1 || found[0].Id != 1 || !reflect.DeepEqual(found[0].Board, easy) {
+		t.Errorf("Find() = %+v, %v, want board 1", found, err)
+	}
+	if _, _, err := s.GetRandomMatching(QueryTechnique(solver.StrategyNameXWingStrategy)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetRandomMatching() error = %v, want %v", err, ErrNotFound)
+	}
+	if _, err := s.Find(QueryTechnique("Guess"), 0, 10); err == nil {
+		t.Errorf("Find() with an unknown technique did not fail")
+	}
+
+	// boards saved after the index was built are indexed as well
+	_, _ = s.SaveNew(easy)
+	found, _ = s.Find(easyQuery, 1, 10)
+	var ids []int
+	for _, stored := range found {
+		ids = append(ids, stored.Id)
+	}
+	if !reflect.DeepEqual(ids, []int{2, 3}) {
+		t.Errorf("Find() after saving = %v, want [2 3]", ids)
+	}
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"fmt"
+	"math/rand"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"github.com/spf13/afero"
+)
+
+const dbCandidateFile = "candidates.bin"
+
+const candidateDataBytes = board.CandidateGridBinaryBytes
+
+/*
+CandidateGridRepo stores Sukaku puzzles under consecutive ids starting at 0. Like SudokuBoardRepo its methods wrap
+ErrNotFound, ErrCorrupt or ErrReadOnly in the errors they return where they apply, other errors come from the file
+system.
+*/
+type CandidateGridRepo interface {
+	// GetRandom returns one of the grids, ErrNotFound if there are none
+	GetRandom() (int, board.CandidateGrid, error)
+	GetByNumber(n int) (int, board.CandidateGrid, error)
+	// SaveNew saves the grid and returns its id
+	SaveNew(grid board.CandidateGrid) (int, error)
+	SaveAll(grids []board.CandidateGrid) error
+}
+
+func NewCandidateGridRepo(dbLocation string) (CandidateGridRepo, func() error, error) {
+	return NewCandidateGridRepoUsingFs(afero.NewBasePathFs(afero.NewOsFs(), dbLocation))
+}
+
+/*
+NewCandidateGridRepoUsingFs opens the repository in the file system, creating its file if needed. If the file can not
+be written the repository is opened read only and saving returns ErrReadOnly.
+*/
+func NewCandidateGridRepoUsingFs(fileSystem afero.Fs) (CandidateGridRepo, func() error, error) {
+	dbFile, err := openRecordFile(fileSystem, dbCandidateFile, candidateFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s := &candidateGridFileRepo{home: dbFile}
+	return s, s.shutdown, nil
+}
+
+/*
+candidateGridFileRepo stores Sukaku puzzles in candidates.bin as fixed size records, the id of a puzzle is the index
+of its record, see recordFile. Each record is the binary form of the grid, see board.CandidateGrid.MarshalBinary.
+*/
+type candidateGridFileRepo struct {
+	home *recordFile
+}
+
+func (s *candidateGridFileRepo) shutdown() error {
+	return s.home.close()
+}
+
+func (s *candidateGridFileRepo) GetRandom() (int, board.CandidateGrid, error) {
+	count := s.home.count()
+	if count == 0 {
+		return 0, board.CandidateGrid{}, fmt.Errorf("the repository is empty: %w", ErrNotFound)
+	}
+	return s.GetByNumber(rand.Intn(count))
+}
+
+func (s *candidateGridFileRepo) GetByNumber(n int) (int, board.CandidateGrid, error) {
+	if n < 0 || n >= s.home.count() {
+		return 0, board.CandidateGrid{}, fmt.Errorf("grid %d: %w", n, ErrNotFound)
+	}
+	data, err := s.home.read(n)
+	if err != nil {
+		return 0, board.CandidateGrid{}, err
+	}
+
+	grid := board.CandidateGrid{}
+	if err := grid.UnmarshalBinary(data); err != nil {
+		return 0, board.CandidateGrid{}, fmt.Errorf("grid %d: %w: %v", n, ErrCorrupt, err)
+	}
+	return n, grid, nil
+}
+
+func (s *candidateGridFileRepo) SaveNew(grid board.CandidateGrid) (int, error) {
+	return s.saveAll([]board.CandidateGrid{grid})
+}
+
+func (s *candidateGridFileRepo) SaveAll(grids []board.CandidateGrid) error {
+	_, err := s.saveAll(grids)
+	return err
+}
+
+// saveAll appends the grids and returns the id of the first of them
+func (s *candidateGridFileRepo) saveAll(grids []board.CandidateGrid) (int, error) {
+	data := make([]byte, 0, candidateDataBytes*len(grids))
+	for _, grid := range grids {
+		encoded, err := grid.MarshalBinary()
+		if err != nil {
+			return 0, err
+		}
+		data = append(data, encoded...)
+	}
+	return s.home.append(data)
+}
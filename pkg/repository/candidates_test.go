@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"github.com/spf13/afero"
+)
+
+func Test_candidateGridFileRepo_SaveNew(t *testing.T) {
+	single := board.CandidateGrid{}
+	single.Allow(0, 0, 1)
+	single.Allow(1, 0, 9)
+	single.Allow(8, 8, 9)
+
+	tests := []struct {
+		name string
+		grid board.CandidateGrid
+		want []byte
+	}{
+		{
+			name: "empty grid",
+			grid: board.CandidateGrid{},
+			want: make([]byte, candidateDataBytes),
+		},
+		{
+			name: "full grid",
+			grid: board.FullCandidateGrid(),
+			want: append(bytes.Repeat([]byte{0xFF}, candidateDataBytes-1), 0x01),
+		},
+		{
+			name: "single candidates",
+			grid: single,
+			want: func() []byte {
+				data := make([]byte, candidateDataBytes)
+				data[0] = 0x01  // r1c1 allows 1, bit 0
+				data[2] = 0x02  // r1c2 allows 9, bit 17
+				data[91] = 0x01 // r9c9 allows 9, bit 728
+				return data
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			s, sd, _ := NewCandidateGridRepoUsingFs(fs)
+			if id, err := s.SaveNew(tt.grid); id != 0 || err != nil {
+				t.Errorf("candidateGridFileRepo.SaveNew() = %d, %v, want 0", id, err)
+			}
+			_ = sd()
+
+			file, _ := fs.Open(dbCandidateFile)
+			content, _ := afero.ReadAll(file)
+			content = content[recordHeaderBytes:]
+			if !reflect.DeepEqual(content, tt.want) {
+				t.Errorf("candidateGridFileRepo.SaveNew() = %v, want %v", content, tt.want)
+			}
+
+			s, sd, _ = NewCandidateGridRepoUsingFs(fs)
+			id, got, err := s.GetByNumber(0)
+			_ = sd()
+			if id != 0 || err != nil || !reflect.DeepEqual(got, tt.grid) {
+				t.Errorf("candidateGridFileRepo.GetByNumber() = %d\n%v, %v, want 0\n%v", id, got.String(), err,
+					tt.grid.String())
+			}
+		})
+	}
+}
+
+func Test_candidateGridFileRepo_GetByNumber(t *testing.T) {
+	first := board.FullCandidateGrid()
+	second := board.CandidateGrid{}
+	second.Allow(4, 4, 5)
+
+	fs := afero.NewMemMapFs()
+	s, sd, _ := NewCandidateGridRepoUsingFs(fs)
+	if _, _, err := s.GetRandom(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("candidateGridFileRepo.GetRandom() of an empty repository error = %v, want %v", err, ErrNotFound)
+	}
+	_ = s.SaveAll([]board.CandidateGrid{first, second})
+
+	tests := []struct {
+		name    string
+		n       int
+		wantId  int
+		want    board.CandidateGrid
+		wantErr error
+	}{
+		{name: "first", n: 0, wantId: 0, want: first},
+		{name: "second", n: 1, wantId: 1, want: second},
+		{name: "beyond file", n: 2, wantErr: ErrNotFound},
+		{name: "negative", n: -1, wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, got, err := s.GetByNumber(tt.n)
+			if id != tt.wantId || !reflect.DeepEqual(got, tt.want) || !errors.Is(err, tt.wantErr) {
+				t.Errorf("candidateGridFileRepo.GetByNumber() = %d\n%v, %v, want %d\n%v, %v", id, got.String(), err,
+					tt.wantId, tt.want.String(), tt.wantErr)
+			}
+		})
+	}
+
+	id, got, err := s.GetRandom()
+	if err != nil || (id != 0 || got != first) && (id != 1 || got != second) {
+		t.Errorf("candidateGridFileRepo.GetRandom() = %d\n%v, %v, not a saved grid", id, got.String(), err)
+	}
+	_ = sd()
+
+	// the unused bits at the end of the second record are set
+	content, _ := afero.ReadFile(fs, dbCandidateFile)
+	content[len(content)-1] |= 0x80
+	_ = afero.WriteFile(fs, dbCandidateFile, content, 0666)
+	s, sd, _ = NewCandidateGridRepoUsingFs(fs)
+	defer sd()
+	if _, _, err := s.GetByNumber(1); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("candidateGridFileRepo.GetByNumber() of a corrupt record error = %v, want %v", err, ErrCorrupt)
+	}
+
+	readOnly, readOnlySd, err := NewCandidateGridRepoUsingFs(afero.NewReadOnlyFs(fs))
+	if err != nil {
+		t.Fatalf("NewCandidateGridRepoUsingFs() of a read only repository error = %v", err)
+	}
+	defer readOnlySd()
+	if _, err := readOnly.SaveNew(first); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("candidateGridFileRepo.SaveNew() of a read only repository error = %v, want %v", err, ErrReadOnly)
+	}
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"github.com/spf13/afero"
+)
+
+const dbQuarantineFile = "boards.quarantine"
+
+/*
+quarantineFormat holds the records CheckSudokuBoardRepo took out of boards.bin, each is the id of the board as 8
+little endian bytes followed by the record as it was, see boardFormat.
+*/
+var quarantineFormat = recordFormat{
+	magic:      [4]byte{'S', 'D', 'K', 'Q'},
+	version:    1,
+	recordSize: 8 + boardRecordBytes,
+}
+
+// Problem is what is wrong with a board of the repository
+type Problem int
+
+const (
+	// ProblemChecksum is a record whose checksum does not match its fields
+	ProblemChecksum Problem = iota
+	// ProblemUndecodable is a record or constraints that do not decode to a board
+	ProblemUndecodable
+	// ProblemInvalid is a board or stored solution breaking the rules, see board.VerifyBoard
+	ProblemInvalid
+	// ProblemDuplicate is a board with the canonical hash of an earlier one
+	ProblemDuplicate
+)
+
+var problemNames = map[Problem]string{
+	ProblemChecksum:    "checksum",
+	ProblemUndecodable: "undecodable",
+	ProblemInvalid:     "invalid",
+	ProblemDuplicate:   "duplicate",
+}
+
+func (p Problem) String() string {
+	if name, found := problemNames[p]; found {
+		return name
+	}
+	return fmt.Sprintf("Problem(%d)", int(p))
+}
+
+// Finding is a problem of board Id, a duplicate has the id of the first board with its hash in Of
+type Finding struct {
+	Id      int
+	Problem Problem
+	Detail  string
+	Of      int
+}
+
+func (f Finding) String() string {
+	if f.Problem == ProblemDuplicate {
+		return fmt.Sprintf("board %d: %s of board %d", f.Id, f.Problem, f.Of)
+	}
+	return fmt.Sprintf("board %d: %s: %s", f.Id, f.Problem, f.Detail)
+}
+
+/*
+CheckReport is what CheckSudokuBoardRepo found. Records counts every record including the Removed ones, Quarantined
+holds the ids of the boards moved to the quarantine file.
+*/
+type CheckReport struct {
+	Records     int
+	Removed     int
+	Findings    []Finding
+	Quarantined []int
+}
+
+// Bad is true if a board was found that can not be used, duplicates are still boards that can be used
+func (r CheckReport) Bad() bool {
+	for _, f := range r.Findings {
+		if f.Problem != ProblemDuplicate {
+			return true
+		}
+	}
+	return false
+}
+
+func CheckSudokuBoardRepo(dbLocation string, quarantine bool) (CheckReport, error) {
+	return CheckSudokuBoardRepoUsingFs(afero.NewBasePathFs(afero.NewOsFs(), dbLocation), quarantine)
+}
+
+/*
+CheckSudokuBoardRepoUsingFs checks every record of the repository in the file system: its checksum, that it decodes
+to a board with its constraints, that the board and any stored solution are valid under board.VerifyBoard and that
+no earlier board has the same canonical hash. Opening the repository finishes or undoes a save that did not finish
+first, see journal. If quarantine is set every board that can not be used is copied to boards.quarantine and removed
+from the repository, keeping its id. Duplicates are only reported as which of them to keep is not for the check to
+decide.
+*/
+func CheckSudokuBoardRepoUsingFs(fileSystem afero.Fs, quarantine bool) (CheckReport, error) {
+	r, sd, err := NewSudokuBoardRepoUsingFs(fileSystem)
+	if err != nil {
+		return CheckReport{}, err
+	}
+	s := r.(*sudokuBoardFileRepo)
+	if quarantine && s.readOnly() {
+		return CheckReport{}, errors.Join(ErrReadOnly, sd())
+	}
+
+	s.mu.Lock()
+	var report CheckReport
+	err = s.locked(func() error {
+		if err := s.catchUp(); err != nil {
+			return err
+		}
+		if err := s.check(&report); err != nil {
+			return err
+		}
+		if quarantine {
+			return s.quarantine(fileSystem, &report)
+		}
+		return nil
+	})
+	s.mu.Unlock()
+	return report, errors.Join(err, sd())
+}
+
+// check adds what it finds in every record to the report, mu and the file lock must be held
+func (s *sudokuBoardFileRepo) check(report *CheckReport) error {
+	report.Records = s.home.count()
+	firstByHash := map[uint64]int{}
+	return s.eachRecord(0, func(id int, record []byte) bool {
+		finding := Finding{Id: id}
+		if err := checkRecord(id, record); err != nil {
+			finding.Problem, finding.Detail = ProblemChecksum, err.Error()
+			report.Findings = append(report.Findings, finding)
+			return true
+		}
+		if isRemoved(record) {
+			report.Removed++
+			return true
+		}
+
+		stored, err := s.recordToStored(id, record)
+		if err != nil {
+			finding.Problem, finding.Detail = ProblemUndecodable, err.Error()
+			report.Findings = append(report.Findings, finding)
+			return true
+		}
+		if !board.VerifyBoard(stored.Board) {
+			finding.Problem, finding.Detail = ProblemInvalid, "the board breaks the rules"
+			report.Findings = append(report.Findings, finding)
+			return true
+		}
+		if stored.Metadata.Unique {
+			solution, err := s.dataToBoard(id, record[boardMetadataBytes+boardDataBytes:boardRecordBytes-boardChecksumBytes])
+			if err != nil {
+				finding.Problem, finding.Detail = ProblemUndecodable, err.Error()
+				report.Findings = append(report.Findings, finding)
+				return true
+			}
+			if !board.VerifyBoard(solution) {
+				finding.Problem, finding.Detail = ProblemInvalid, "the stored solution breaks the rules"
+				report.Findings = append(report.Findings, finding)
+				return true
+			}
+		}
+
+		if hash := stored.Metadata.Hash; hash != 0 {
+			if first, found := firstByHash[hash]; found {
+				finding.Problem, finding.Of = ProblemDuplicate, first
+				report.Findings = append(report.Findings, finding)
+			} else {
+				firstByHash[hash] = id
+			}
+		}
+		return true
+	})
+}
+
+/*
+quarantine copies the record of every board the report found unusable to the quarantine file, then replaces it with a
+removed record through the journal. A board is copied before it is removed so a save that does not finish leaves it in
+both files rather than in neither.
+*/
+func (s *sudokuBoardFileRepo) quarantine(fileSystem afero.Fs, report *CheckReport) error {
+	var quarantined []byte
+	var writes []journalWrite
+	for _, f := range report.Findings {
+		if f.Problem == ProblemDuplicate {
+			continue
+		}
+		record, err := s.home.read(f.Id)
+		if err != nil {
+			return err
+		}
+		quarantined = binary.LittleEndian.AppendUint64(quarantined, uint64(f.Id))
+		quarantined = append(quarantined, record...)
+		writes = append(writes, s.home.recordWrite(f.Id, removedRecord()))
+		report.Quarantined = append(report.Quarantined, f.Id)
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+
+	side, err := openRecordFile(fileSystem, dbQuarantineFile, quarantineFormat)
+	if err != nil {
+		return err
+	}
+	_, err = side.append(quarantined)
+	if err = errors.Join(err, side.close()); err != nil {
+		return fmt.Errorf("%s: %w", dbQuarantineFile, err)
+	}
+	generationWrite, header := s.home.generationWrite()
+	if err := s.journal.commit(append(writes, generationWrite)); err != nil {
+		return err
+	}
+	s.home.header = header
+	s.index = nil
+	return nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"github.com/spf13/afero"
+)
+
+func TestCheckSudokuBoardRepo(t *testing.T) {
+	puzzle, _ := board.ParseLine(
+		"53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79")
+	transposed, _ := board.Transpose().Apply(puzzle)
+	fs := afero.NewMemMapFs()
+	s, sd, _ := NewSudokuBoardRepoUsingFs(fs)
+	_ = s.SaveAll([]*board.SudokuBoard{
+		puzzle,
+		board.FromNumbers([9][9]int{{1, 1}}),
+		board.FromNumbers([9][9]int{{1, 2}}),
+		transposed,
+		board.FromNumbers([9][9]int{{1, 2, 3}}),
+	})
+	_ = sd()
+
+	content, _ := afero.ReadFile(fs, dbBoardFile)
+	content[recordHeaderBytes+2*boardRecordBytes+boardMetadataBytes]++
+	_ = afero.WriteFile(fs, dbBoardFile, content, 0666)
+	_ = afero.WriteFile(fs, dbConstraintFile, []byte("4 thermo:r0c0\n"), 0666)
+
+	want := []Finding{
+		{Id: 1, Problem: ProblemInvalid, Detail: "the board breaks the rules"},
+		{Id: 2, Problem: ProblemChecksum, Detail: "board 2: corrupt repository: checksum does not match"},
+		{Id: 3, Problem: ProblemDuplicate, Of: 0},
+		{Id: 4, Problem: ProblemUndecodable},
+	}
+	report, err := CheckSudokuBoardRepoUsingFs(fs, false)
+	if err != nil {
+		t.Fatalf("CheckSudokuBoardRepoUsingFs() error = %v", err)
+	}
+	report.Findings[3].Detail = "" // the parse error of the constraints is not under test
+	if report.Records != 5 || !reflect.DeepEqual(report.Findings, want) || !report.Bad() {
+		t.Errorf("CheckSudokuBoardRepoUsingFs() = %+v, want %+v", report, want)
+	}
+	if report.Quarantined != nil {
+		t.Errorf("CheckSudokuBoardRepoUsingFs() quarantined %v without being asked to", report.Quarantined)
+	}
+
+	report, _ = CheckSudokuBoardRepoUsingFs(fs, true)
+	if !reflect.DeepEqual(report.Quarantined, []int{1, 2, 4}) {
+		t.Errorf("CheckSudokuBoardRepoUsingFs() quarantined %v, want [1 2 4]", report.Quarantined)
+	}
+	quarantined, _ := afero.ReadFile(fs, dbQuarantineFile)
+	quarantined = quarantined[recordHeaderBytes:]
+	first := content[recordHeaderBytes+boardRecordBytes:][:boardRecordBytes]
+	if len(quarantined) != 3*quarantineFormat.recordSize || binary.LittleEndian.Uint64(quarantined) != 1 ||
+		!reflect.DeepEqual(quarantined[8:8+boardRecordBytes], first) {
+		t.Errorf("%s does not hold the quarantined records", dbQuarantineFile)
+	}
+
+	// the ids of the boards left do not change
+	s, sd, _ = NewSudokuBoardRepoUsingFs(fs)
+	if count, _ := s.Count(); count != 5 {
+		t.Errorf("Count() = %d, want 5", count)
+	}
+	if _, _, err := s.GetByNumber(2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetByNumber(2) error = %v, want %v", err, ErrNotFound)
+	}
+	if list, _ := s.List(0, 5); len(list) != 2 || list[0].Id != 0 || list[1].Id != 3 {
+		t.Errorf("List() = %+v, want boards 0 and 3", list)
+	}
+	if count, _ := s.CountMatching(Query{}); count != 2 {
+		t.Errorf("CountMatching() = %d, want 2", count)
+	}
+	for i := 0; i < 20; i++ {
+		if id, _, err := s.GetRandom(); err != nil || (id != 0 && id != 3) {
+			t.Fatalf("GetRandom() = %d, %v, want board 0 or 3", id, err)
+		}
+	}
+	_ = sd()
+
+	report, _ = CheckSudokuBoardRepoUsingFs(fs, false)
+	if report.Removed != 3 || report.Bad() {
+		t.Errorf("CheckSudokuBoardRepoUsingFs() after quarantining = %+v", report)
+	}
+	if _, err := CheckSudokuBoardRepoUsingFs(afero.NewReadOnlyFs(fs), true); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("CheckSudokuBoardRepoUsingFs() of a read only repository error = %v, want %v", err, ErrReadOnly)
+	}
+}
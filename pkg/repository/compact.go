@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/afero"
+)
+
+func CompactSudokuBoardRepo(dbLocation string) (map[int]int, error) {
+	return CompactSudokuBoardRepoUsingFs(afero.NewBasePathFs(afero.NewOsFs(), dbLocation))
+}
+
+/*
+CompactSudokuBoardRepoUsingFs rewrites the repository in the file system without the tombstones of removed boards and
+returns the new id of every board that is left by its old id. The boards keep their order, so only boards after a
+removed one get a new id. Records that fail their checksum are kept as they are, see CheckSudokuBoardRepo. Ids held
+elsewhere, such as those in boards.quarantine or by processes that have the repository open, are not changed, so the
+repository should not be in use while it is compacted. Both files are rewritten through the journal so the repository
+is either compacted or left as it was.
+*/
+func CompactSudokuBoardRepoUsingFs(fileSystem afero.Fs) (map[int]int, error) {
+	r, sd, err := NewSudokuBoardRepoUsingFs(fileSystem)
+	if err != nil {
+		return nil, err
+	}
+	s := r.(*sudokuBoardFileRepo)
+	if s.readOnly() {
+		return nil, errors.Join(ErrReadOnly, sd())
+	}
+
+	s.mu.Lock()
+	var newIds map[int]int
+	err = s.locked(func() error {
+		if err := s.catchUp(); err != nil {
+			return err
+		}
+		newIds, err = s.compact()
+		return err
+	})
+	s.mu.Unlock()
+	return newIds, errors.Join(err, sd())
+}
+
+// compact drops the tombstones and renumbers the constraints, mu and the file lock must be held
+func (s *sudokuBoardFileRepo) compact() (map[int]int, error) {
+	newIds := map[int]int{}
+	var records []byte
+	err := s.eachRecord(0, func(id int, record []byte) bool {
+		if checkRecord(id, record) == nil && isRemoved(record) {
+			return true
+		}
+		newIds[id] = len(newIds)
+		records = append(records, record...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	writes, header, err := s.home.rewriteWrites(records)
+	if err != nil {
+		return nil, err
+	}
+	var lines []byte
+	constraintsById := map[int]string{}
+	for id := 0; id < s.home.count(); id++ {
+		newId, kept := newIds[id]
+		encoded, found := s.constraintsById[id]
+		if kept && found {
+			constraintsById[newId] = encoded
+			lines = fmt.Appendf(lines, "%d %s\n", newId, encoded)
+		}
+	}
+	writes = append(writes, journalWrite{name: dbConstraintFile, offset: 0, data: lines, truncate: true})
+	if err := s.journal.commit(writes); err != nil {
+		return nil, err
+	}
+
+	s.home.header = header
+	s.constraintsById = constraintsById
+	s.index = nil
+	return newIds, nil
+}
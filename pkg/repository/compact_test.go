@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"github.com/spf13/afero"
+)
+
+func TestCompactSudokuBoardRepo(t *testing.T) {
+	var boards []*board.SudokuBoard
+	for v := 1; v <= 6; v++ {
+		boards = append(boards, board.FromNumbers([9][9]int{{v}}))
+	}
+	boards[3] = boards[3].AddConstraints(board.AntiKnightConstraint{})
+	boards[4] = boards[4].AddConstraints(board.AntiKingConstraint{})
+
+	fs := afero.NewMemMapFs()
+	s, sd, _ := NewSudokuBoardRepoUsingFs(fs)
+	_ = s.SaveAll(boards)
+	_ = s.Delete(1)
+	_ = s.Delete(4)
+	_ = sd()
+
+	newIds, err := CompactSudokuBoardRepoUsingFs(fs)
+	if err != nil {
+		t.Fatalf("CompactSudokuBoardRepoUsingFs() error = %v", err)
+	}
+	want := map[int]int{0: 0, 2: 1, 3: 2, 5: 3}
+	if !reflect.DeepEqual(newIds, want) {
+		t.Errorf("CompactSudokuBoardRepoUsingFs() = %v, want %v", newIds, want)
+	}
+
+	s, sd, _ = NewSudokuBoardRepoUsingFs(fs)
+	defer sd()
+	if count, _ := s.Count(); count != len(want) {
+		t.Errorf("Count() = %d, want %d", count, len(want))
+	}
+	for oldId, newId := range want {
+		if _, got, err := s.GetByNumber(newId); err != nil || !reflect.DeepEqual(got, boards[oldId]) {
+			t.Errorf("GetByNumber(%d) = \n%v %v, %v, want \n%v %v", newId, got, got.Constraints(), err, boards[oldId],
+				boards[oldId].Constraints())
+		}
+	}
+	if content, _ := afero.ReadFile(fs, dbConstraintFile); string(content) != "2 antiknight\n" {
+		t.Errorf("constraint file = %q, want %q", content, "2 antiknight\n")
+	}
+
+	if _, err := CompactSudokuBoardRepoUsingFs(afero.NewReadOnlyFs(fs)); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("CompactSudokuBoardRepoUsingFs() of a read only repository error = %v, want %v", err, ErrReadOnly)
+	}
+}
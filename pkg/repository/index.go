@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+/*
+Query selects boards by their metadata, the zero value selects every board. Difficulties lists the difficulties wanted,
+any of them will do. If Technique is set the solve path has to use that strategy. MinClues and MaxClues bound the
+number of clues, a MaxClues of 0 leaves it unbounded. Boards without a single solution are not rated, so they are
+left out as soon as a difficulty or technique is asked for.
+*/
+type Query struct {
+	Difficulties []solver.StrategyDifficulty
+	Technique    solver.StrategyName
+	MinClues     int
+	MaxClues     int
+}
+
+// QueryDifficulty selects the boards of the difficulty
+func QueryDifficulty(d solver.StrategyDifficulty) Query {
+	return Query{Difficulties: []solver.StrategyDifficulty{d}}
+}
+
+// QueryTechnique selects the boards whose solve path uses the strategy
+func QueryTechnique(name solver.StrategyName) Query {
+	return Query{Technique: name}
+}
+
+// Matches checks the metadata of a board is what the query selects
+func (q Query) Matches(m Metadata) bool {
+	compiled, err := q.compile()
+	if err != nil {
+		return false
+	}
+	strategies, err := strategyMask(m.Rating.Strategies)
+	if err != nil {
+		return false
+	}
+	return compiled.matches(indexEntry{
+		unique:     m.Unique,
+		difficulty: m.Rating.Difficulty,
+		clues:      m.Clues,
+		strategies: strategies,
+	})
+}
+
+// compiledQuery is a Query in the terms of the index
+type compiledQuery struct {
+	rated        bool
+	difficulties []solver.StrategyDifficulty
+	technique    int
+	minClues     int
+	maxClues     int
+}
+
+func (q Query) compile() (compiledQuery, error) {
+	c := compiledQuery{difficulties: q.Difficulties, technique: -1, minClues: q.MinClues, maxClues: q.MaxClues}
+	if c.maxClues == 0 {
+		c.maxClues = 81
+	}
+	if c.minClues < 0 || c.minClues > c.maxClues || c.maxClues > 81 {
+		return c, fmt.Errorf("clue range %d to %d is empty", q.MinClues, q.MaxClues)
+	}
+	if q.Technique != "" {
+		c.technique = slices.Index(strategyBits, q.Technique)
+		if c.technique == -1 {
+			return c, fmt.Errorf("unknown technique %q", q.Technique)
+		}
+	}
+	c.rated = len(c.difficulties) > 0 || c.technique != -1
+	return c, nil
+}
+
+// all is true when the query selects every board
+func (c compiledQuery) all() bool {
+	return !c.rated && c.minClues == 0 && c.maxClues == 81
+}
+
+func (c compiledQuery) matches(e indexEntry) bool {
+	if e.clues < c.minClues || e.clues > c.maxClues {
+		return false
+	}
+	if !c.rated {
+		return true
+	}
+	if !e.unique || (len(c.difficulties) > 0 && !slices.Contains(c.difficulties, e.difficulty)) {
+		return false
+	}
+	return c.technique == -1 || e.strategies&(1<<c.technique) != 0
+}
+
+// indexEntry is the part of the metadata of a board the index selects on
+type indexEntry struct {
+	removed    bool
+	unique     bool
+	difficulty solver.StrategyDifficulty
+	clues      int
+	strategies uint32
+}
+
+// indexEntryOf reads the entry from the metadata of a record, see boardFormat
+func indexEntryOf(metadata []byte) indexEntry {
+	e := indexEntry{
+		removed: metadata[0]&boardFlagRemoved != 0,
+		unique:  metadata[0]&boardFlagUnique != 0,
+		clues:   int(metadata[2]),
+	}
+	if e.unique {
+		e.difficulty = solver.StrategyDifficulty(metadata[1])
+		e.strategies = binary.LittleEndian.Uint32(metadata[4:])
+	}
+	return e
+}
+
+/*
+boardIndex is the secondary index of the repository. It keeps an entry for every board and, for each difficulty,
+strategy and clue count, the sorted ids of the boards having it. A query starts from the shortest of the id lists that
+apply to it and checks the entries of those ids.
+*/
+type boardIndex struct {
+	entries      []indexEntry
+	byDifficulty map[solver.StrategyDifficulty][]int
+	byStrategy   [32][]int
+	byClues      [82][]int
+}
+
+func newBoardIndex() *boardIndex {
+	return &boardIndex{byDifficulty: map[solver.StrategyDifficulty][]int{}}
+}
+
+// add indexes the next board, ids are given in order
+func (x *boardIndex) add(e indexEntry) {
+	id := len(x.entries)
+	x.entries = append(x.entries, e)
+	if e.removed || e.clues > 81 {
+		return // a removed board or a corrupt record, no query selects it
+	}
+	x.byClues[e.clues] = append(x.byClues[e.clues], id)
+	if !e.unique {
+		return
+	}
+	x.byDifficulty[e.difficulty] = append(x.byDifficulty[e.difficulty], id)
+	for bit := range x.byStrategy {
+		if e.strategies&(1<<bit) != 0 {
+			x.byStrategy[bit] = append(x.byStrategy[bit], id)
+		}
+	}
+}
+
+// find returns the ids of the boards the query selects in order
+func (x *boardIndex) find(c compiledQuery) []int {
+	var candidates []int
+	if c.all() {
+		candidates = make([]int, 0, len(x.entries))
+		for id, e := range x.entries {
+			if !e.removed {
+				candidates = append(candidates, id)
+			}
+		}
+		return candidates
+	}
+
+	var options [][][]int
+	if c.technique != -1 {
+		options = append(options, [][]int{x.byStrategy[c.technique]})
+	}
+	if len(c.difficulties) > 0 {
+		var lists [][]int
+		for _, d := range c.difficulties {
+			lists = append(lists, x.byDifficulty[d])
+		}
+		options = append(options, lists)
+	}
+	if c.minClues > 0 || c.maxClues < 81 {
+		options = append(options, x.byClues[c.minClues:c.maxClues+1])
+	}
+	shortest := slices.MinFunc(options, func(a, b [][]int) int { return idCount(a) - idCount(b) })
+	for _, list := range shortest {
+		candidates = append(candidates, list...)
+	}
+	if len(shortest) > 1 {
+		slices.Sort(candidates)
+		candidates = slices.Compact(candidates)
+	}
+
+	ids := make([]int, 0, len(candidates))
+	for _, id := range candidates {
+		if c.matches(x.entries[id]) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func idCount(lists [][]int) int {
+	count := 0
+	for _, list := range lists {
+		count += len(list)
+	}
+	return count
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+func TestQuery_Matches(t *testing.T) {
+	medium := Metadata{
+		Unique: true,
+		Rating: solver.Rating{
+			Difficulty: solver.StrategyDifficultyMedium,
+			Strategies: []solver.StrategyName{solver.StrategyNameLastInRowStrategy, solver.StrategyNameNakedPairStrategy},
+		},
+		Clues: 26,
+	}
+	unrated := Metadata{Clues: 20}
+
+	tests := []struct {
+		name     string
+		query    Query
+		metadata Metadata
+		want     bool
+	}{
+		{name: "everything", query: Query{}, metadata: unrated, want: true},
+		{name: "difficulty", query: QueryDifficulty(solver.StrategyDifficultyMedium), metadata: medium, want: true},
+		{name: "other difficulty", query: QueryDifficulty(solver.StrategyDifficultyEasy), metadata: medium, want: false},
+		{
+			name: "any of the difficulties",
+			query: Query{
+				Difficulties: []solver.StrategyDifficulty{solver.StrategyDifficultyEasy, solver.StrategyDifficultyMedium},
+			},
+			metadata: medium,
+			want:     true,
+		},
+		{name: "technique", query: QueryTechnique(solver.StrategyNameNakedPairStrategy), metadata: medium, want: true},
+		{name: "unused technique", query: QueryTechnique(solver.StrategyNameXWingStrategy), metadata: medium, want: false},
+		{name: "unknown technique", query: QueryTechnique("Guess"), metadata: medium, want: false},
+		{name: "unrated board", query: QueryDifficulty(solver.StrategyDifficultyEasy), metadata: unrated, want: false},
+		{name: "clue range", query: Query{MinClues: 20, MaxClues: 26}, metadata: medium, want: true},
+		{name: "too few clues", query: Query{MinClues: 27}, metadata: medium, want: false},
+		{name: "too many clues", query: Query{MaxClues: 25}, metadata: medium, want: false},
+		{name: "empty clue range", query: Query{MinClues: 30, MaxClues: 20}, metadata: medium, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Matches(tt.metadata); got != tt.want {
+				t.Errorf("Query.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_boardIndex_find(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	index := newBoardIndex()
+	var entries []indexEntry
+	for id := 0; id < 500; id++ {
+		e := indexEntry{unique: rng.Intn(4) != 0, clues: 17 + rng.Intn(20)}
+		if e.unique {
+			e.difficulty = solver.StrategyDifficulty(rng.Intn(5))
+			e.strategies = uint32(rng.Intn(1 << len(strategyBits)))
+		}
+		entries = append(entries, e)
+		index.add(e)
+	}
+
+	queries := []Query{
+		{},
+		QueryDifficulty(solver.StrategyDifficultyHard),
+		{Difficulties: []solver.StrategyDifficulty{
+			solver.StrategyDifficultyEasy, solver.StrategyDifficultyEasy, solver.StrategyDifficultyImpossible,
+		}},
+		QueryTechnique(solver.StrategyNameXWingStrategy),
+		{MinClues: 30},
+		{MaxClues: 18},
+		{
+			Difficulties: []solver.StrategyDifficulty{solver.StrategyDifficultyMedium},
+			Technique:    solver.StrategyNameNakedPairStrategy,
+			MinClues:     20,
+			MaxClues:     30,
+		},
+	}
+	for _, q := range queries {
+		compiled, err := q.compile()
+		if err != nil {
+			t.Fatalf("compile(%+v) error = %v", q, err)
+		}
+		want := []int{}
+		for id, e := range entries {
+			if compiled.matches(e) {
+				want = append(want, id)
+			}
+		}
+		if got := index.find(compiled); !reflect.DeepEqual(got, want) {
+			t.Errorf("find(%+v) = %v, want %v", q, got, want)
+		}
+	}
+}
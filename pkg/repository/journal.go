@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	osConst "os"
+
+	"github.com/spf13/afero"
+)
+
+const dbJournalFile = "boards.journal"
+
+var journalMagic = [4]byte{'S', 'D', 'K', 'J'}
+
+const journalVersion = 2
+
+// journalFlagTruncate marks a write that cuts the file at the end of its data
+const journalFlagTruncate = 1 << 0
+
+// journalWrite is data to write at offset of the named file, if truncate is set the file ends with the data
+type journalWrite struct {
+	name     string
+	offset   int64
+	data     []byte
+	truncate bool
+}
+
+/*
+journal makes a set of writes to several files atomic. The writes are first written to the journal file and synced,
+then made to the files, then the journal is removed. If the process dies part way the journal is found when the
+repository is opened again, see recoverJournal: a whole journal is written again, so every write lands, and one that is
+cut short is removed, so none do. Writing again is safe as every write is to a fixed offset. All numbers are little
+endian:
+
+	size  field
+	4     magic
+	2     version
+	2     reserved, always 0
+	4     number of writes
+	        per write:
+	2       length of the file name
+	n       file name
+	8       offset
+	1       flags, see journalFlagTruncate
+	4       length of the data
+	n       data
+	4     CRC-32 (IEEE) of everything before it
+
+Version 1 had no flags, a journal of another version is taken to be cut short.
+*/
+type journal struct {
+	fs    afero.Fs
+	name  string
+	files map[string]afero.File
+}
+
+func newJournal(fs afero.Fs, files map[string]afero.File) *journal {
+	return &journal{fs: fs, name: dbJournalFile, files: files}
+}
+
+/*
+commit makes the writes to the files of the journal. If writing the journal fails none of the writes are made, if
+making them fails the journal is kept so they are finished when the repository is opened again.
+*/
+func (j *journal) commit(writes []journalWrite) error {
+	file, err := j.fs.OpenFile(j.name, osConst.O_CREATE|osConst.O_TRUNC|osConst.O_WRONLY, 0666)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", j.name, err)
+	}
+	_, err = file.Write(encodeJournal(writes))
+	if err == nil {
+		err = file.Sync()
+	}
+	err = errors.Join(err, file.Close())
+	if err != nil {
+		_ = j.fs.Remove(j.name)
+		return fmt.Errorf("writing %s: %w", j.name, err)
+	}
+
+	if err := applyJournal(writes, func(name string) (afero.File, error) {
+		if file, found := j.files[name]; found {
+			return file, nil
+		}
+		return nil, fmt.Errorf("%s is not a file of the journal", name)
+	}); err != nil {
+		return err
+	}
+	return j.fs.Remove(j.name)
+}
+
+// applyJournal makes the writes and syncs every file written to
+func applyJournal(writes []journalWrite, open func(name string) (afero.File, error)) error {
+	var written []afero.File
+	for _, w := range writes {
+		file, err := open(w.name)
+		if err != nil {
+			return err
+		}
+		if _, err := file.WriteAt(w.data, w.offset); err != nil {
+			return fmt.Errorf("writing %s: %w", w.name, err)
+		}
+		if w.truncate {
+			if err := file.Truncate(w.offset + int64(len(w.data))); err != nil {
+				return fmt.Errorf("truncating %s: %w", w.name, err)
+			}
+		}
+		written = append(written, file)
+	}
+	for _, file := range written {
+		if err := file.Sync(); err != nil {
+			return fmt.Errorf("syncing %s: %w", file.Name(), err)
+		}
+	}
+	return nil
+}
+
+/*
+recoverJournal finishes the writes of a journal left behind by a process that died while saving, see journal. A
+journal that was cut short is removed. A repository that is read only can not finish them, opening it fails with
+ErrReadOnly instead.
+*/
+func recoverJournal(fs afero.Fs, readOnly bool) error {
+	content, err := afero.ReadFile(fs, dbJournalFile)
+	if errors.Is(err, osConst.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	writes, valid := decodeJournal(content)
+	if readOnly {
+		if valid {
+			return fmt.Errorf("%s: has unfinished writes: %w", dbJournalFile, ErrReadOnly)
+		}
+		return nil
+	}
+	if !valid {
+		return fs.Remove(dbJournalFile)
+	}
+
+	opened := map[string]afero.File{}
+	err = applyJournal(writes, func(name string) (afero.File, error) {
+		if file, found := opened[name]; found {
+			return file, nil
+		}
+		file, err := fs.OpenFile(name, osConst.O_CREATE|osConst.O_RDWR, 0666)
+		if err != nil {
+			return nil, err
+		}
+		opened[name] = file
+		return file, nil
+	})
+	for _, file := range opened {
+		err = errors.Join(err, file.Close())
+	}
+	if err != nil {
+		return fmt.Errorf("%s: recovering: %w", dbJournalFile, err)
+	}
+	return fs.Remove(dbJournalFile)
+}
+
+func encodeJournal(writes []journalWrite) []byte {
+	var buf bytes.Buffer
+	buf.Write(journalMagic[:])
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(journalVersion))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(0))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(writes)))
+	for _, w := range writes {
+		_ = binary.Write(&buf, binary.LittleEndian, uint16(len(w.name)))
+		buf.WriteString(w.name)
+		_ = binary.Write(&buf, binary.LittleEndian, w.offset)
+		var flags byte
+		if w.truncate {
+			flags |= journalFlagTruncate
+		}
+		buf.WriteByte(flags)
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(len(w.data)))
+		buf.Write(w.data)
+	}
+	_ = binary.Write(&buf, binary.LittleEndian, crc32.ChecksumIEEE(buf.Bytes()))
+	return buf.Bytes()
+}
+
+// decodeJournal reads the writes of a journal, valid is false if it was cut short or is not a journal
+func decodeJournal(content []byte) (writes []journalWrite, valid bool) {
+	if len(content) < 16 {
+		return nil, false
+	}
+	body, sum := content[:len(content)-4], content[len(content)-4:]
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(sum) || !bytes.Equal(body[:4], journalMagic[:]) ||
+		binary.LittleEndian.Uint16(body[4:]) != journalVersion {
+		return nil, false
+	}
+
+	count := binary.LittleEndian.Uint32(body[8:])
+	rest := body[12:]
+	for i := uint32(0); i < count; i++ {
+		if len(rest) < 2 {
+			return nil, false
+		}
+		nameSize := int(binary.LittleEndian.Uint16(rest))
+		if len(rest) < 2+nameSize+13 {
+			return nil, false
+		}
+		w := journalWrite{name: string(rest[2 : 2+nameSize])}
+		rest = rest[2+nameSize:]
+		w.offset = int64(binary.LittleEndian.Uint64(rest))
+		w.truncate = rest[8]&journalFlagTruncate != 0
+		dataSize := int(binary.LittleEndian.Uint32(rest[9:]))
+		rest = rest[13:]
+		if len(rest) < dataSize {
+			return nil, false
+		}
+		w.data = rest[:dataSize]
+		rest = rest[dataSize:]
+		writes = append(writes, w)
+	}
+	return writes, len(rest) == 0
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"errors"
+	osConst "os"
+	"reflect"
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"github.com/spf13/afero"
+)
+
+func Test_encodeJournal(t *testing.T) {
+	writes := []journalWrite{
+		{name: dbBoardFile, offset: 32, data: []byte{1, 2, 3}},
+		{name: dbConstraintFile, offset: 7, data: []byte("4 antiking\n")},
+		{name: dbBoardFile, offset: 0, data: []byte{}, truncate: true},
+	}
+	content := encodeJournal(writes)
+	if got, valid := decodeJournal(content); !valid || !reflect.DeepEqual(got, writes) {
+		t.Errorf("decodeJournal() = %v, %v, want %v", got, valid, writes)
+	}
+	for size := 0; size < len(content); size++ {
+		if _, valid := decodeJournal(content[:size]); valid {
+			t.Errorf("decodeJournal() of the first %d bytes is valid", size)
+		}
+	}
+	content[20]++
+	if _, valid := decodeJournal(content); valid {
+		t.Errorf("decodeJournal() of a changed journal is valid")
+	}
+}
+
+// failingFs fails writing at an offset of the named file, like a process dying part way through a save would
+type failingFs struct {
+	afero.Fs
+	name string
+}
+
+func (fs failingFs) OpenFile(name string, flag int, perm osConst.FileMode) (afero.File, error) {
+	file, err := fs.Fs.OpenFile(name, flag, perm)
+	if err != nil || name != fs.name {
+		return file, err
+	}
+	return failingFile{file}, nil
+}
+
+type failingFile struct {
+	afero.File
+}
+
+func (failingFile) WriteAt([]byte, int64) (int, error) {
+	return 0, errors.New("failed on purpose")
+}
+
+func Test_sudokuBoardFileRepo_FinishesSave(t *testing.T) {
+	plain := board.FromNumbers([9][9]int{{1, 2, 3}})
+	variant := board.FromNumbers([9][9]int{{4, 5, 6}}).AddConstraints(board.AntiKingConstraint{})
+
+	fs := afero.NewMemMapFs()
+	s, sd, _ := NewSudokuBoardRepoUsingFs(fs)
+	_, _ = s.SaveNew(plain)
+	_ = sd()
+
+	// the boards are written but the constraints are not
+	s, sd, _ = NewSudokuBoardRepoUsingFs(failingFs{Fs: fs, name: dbConstraintFile})
+	if err := s.SaveAll([]*board.SudokuBoard{plain, variant}); err == nil {
+		t.Fatalf("SaveAll() did not fail")
+	}
+	_ = sd()
+	if exists, _ := afero.Exists(fs, dbJournalFile); !exists {
+		t.Fatalf("%s is not kept after a failed save", dbJournalFile)
+	}
+
+	s, sd, err := NewSudokuBoardRepoUsingFs(fs)
+	if err != nil {
+		t.Fatalf("NewSudokuBoardRepoUsingFs() error = %v", err)
+	}
+	defer sd()
+	if count, _ := s.Count(); count != 3 {
+		t.Errorf("Count() = %d, want 3", count)
+	}
+	if _, got, err := s.GetByNumber(2); err != nil || !reflect.DeepEqual(got, variant) {
+		t.Errorf("GetByNumber(2) = \n%v %v, %v, want \n%v %v", got, got.Constraints(), err, variant, variant.Constraints())
+	}
+	if exists, _ := afero.Exists(fs, dbJournalFile); exists {
+		t.Errorf("%s is left behind", dbJournalFile)
+	}
+}
+
+func Test_sudokuBoardFileRepo_UndoesSave(t *testing.T) {
+	plain := board.FromNumbers([9][9]int{{1, 2, 3}})
+	variant := board.FromNumbers([9][9]int{{4, 5, 6}}).AddConstraints(board.AntiKingConstraint{})
+
+	fs := afero.NewMemMapFs()
+	s, sd, _ := NewSudokuBoardRepoUsingFs(fs)
+	_ = s.SaveAll([]*board.SudokuBoard{plain, variant})
+	_ = sd()
+	want, _ := afero.ReadFile(fs, dbBoardFile)
+	wantConstraints, _ := afero.ReadFile(fs, dbConstraintFile)
+
+	// a save that died while writing the journal leaves the journal cut short, a process without the journal could
+	// have left part of a record and a line of constraints
+	journal := encodeJournal([]journalWrite{{name: dbBoardFile, offset: int64(len(want)), data: make([]byte, 10)}})
+	_ = afero.WriteFile(fs, dbJournalFile, journal[:len(journal)-1], 0666)
+	_ = afero.WriteFile(fs, dbBoardFile, append(want, 1, 2, 3), 0666)
+	_ = afero.WriteFile(fs, dbConstraintFile, append(wantConstraints, "2 antiking\n3 anti"...), 0666)
+
+	s, sd, err := NewSudokuBoardRepoUsingFs(fs)
+	if err != nil {
+		t.Fatalf("NewSudokuBoardRepoUsingFs() error = %v", err)
+	}
+	if count, _ := s.Count(); count != 2 {
+		t.Errorf("Count() = %d, want 2", count)
+	}
+	if _, got, err := s.GetByNumber(1); err != nil || !reflect.DeepEqual(got, variant) {
+		t.Errorf("GetByNumber(1) = \n%v %v, %v, want \n%v %v", got, got.Constraints(), err, variant, variant.Constraints())
+	}
+	_ = sd()
+
+	if got, _ := afero.ReadFile(fs, dbBoardFile); !reflect.DeepEqual(got, want) {
+		t.Errorf("%s is not cut back to its records", dbBoardFile)
+	}
+	if got, _ := afero.ReadFile(fs, dbConstraintFile); string(got) != string(wantConstraints) {
+		t.Errorf("%s = %q, want %q", dbConstraintFile, got, wantConstraints)
+	}
+	if exists, _ := afero.Exists(fs, dbJournalFile); exists {
+		t.Errorf("%s is left behind", dbJournalFile)
+	}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	osConst "os"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+const dbLockFile = "boards.lock"
+
+const (
+	// lockRetry is how long to wait before trying to take a lock held by another process again
+	lockRetry = 5 * time.Millisecond
+	// lockTimeout is how long to wait for a lock before giving up with ErrLocked
+	lockTimeout = 10 * time.Second
+	// staleLockAge is how old a lock has to be before it is taken to be left behind by a process that died holding it
+	staleLockAge = 10 * time.Minute
+)
+
+/*
+fileLock is the advisory lock the processes sharing a repository hold while they write to it or read what the others
+wrote. The lock is a directory rather than a file: creating a directory fails if it exists on every file system,
+while afero.MemMapFs checks for a file and creates it in two steps so O_EXCL does not make two processes exclude each
+other there. Only processes using fileLock are kept out, the lock does not stop anything else writing to the files.
+*/
+type fileLock struct {
+	fs   afero.Fs
+	name string
+}
+
+func newFileLock(fs afero.Fs) *fileLock {
+	return &fileLock{fs: fs, name: dbLockFile}
+}
+
+// lock waits for the lock until lockTimeout, a lock older than staleLockAge is removed and taken over
+func (l *fileLock) lock() error {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		err := l.fs.Mkdir(l.name, 0777)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, osConst.ErrExist) {
+			return fmt.Errorf("taking %s: %w", l.name, err)
+		}
+		if stat, err := l.fs.Stat(l.name); err == nil && time.Since(stat.ModTime()) > staleLockAge {
+			removed, err := l.takeOver(stat.ModTime())
+			if err != nil {
+				return err
+			}
+			if removed {
+				continue
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s held for over %v: %w", l.name, lockTimeout, ErrLocked)
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+/*
+takeOver removes the stale lock found last changed at modTime. Removing it straight away could remove a lock another
+waiter took after taking the stale one over, so every waiter that found it tries to create a guard named after modTime
+and only the one that does removes the lock, and only if it still has that time. A guard left behind by a process that
+died taking over is removed once it is stale itself.
+*/
+func (l *fileLock) takeOver(modTime time.Time) (bool, error) {
+	guard := fmt.Sprintf("%s.%d", l.name, modTime.UnixNano())
+	if err := l.fs.Mkdir(guard, 0777); err != nil {
+		if !errors.Is(err, osConst.ErrExist) {
+			return false, fmt.Errorf("taking over %s: %w", l.name, err)
+		}
+		if stat, err := l.fs.Stat(guard); err == nil && time.Since(stat.ModTime()) > staleLockAge {
+			_ = l.fs.Remove(guard)
+		}
+		return false, nil
+	}
+	defer func() { _ = l.fs.Remove(guard) }()
+
+	stat, err := l.fs.Stat(l.name)
+	if err != nil || !stat.ModTime().Equal(modTime) {
+		return false, nil
+	}
+	if err := l.fs.Remove(l.name); err != nil {
+		return false, fmt.Errorf("taking over %s: %w", l.name, err)
+	}
+	return true, nil
+}
+
+func (l *fileLock) unlock() error {
+	if err := l.fs.Remove(l.name); err != nil {
+		return fmt.Errorf("releasing %s: %w", l.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"github.com/spf13/afero"
+)
+
+func Test_fileLock(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	l := newFileLock(fs)
+	if err := l.lock(); err != nil {
+		t.Fatalf("lock() error = %v", err)
+	}
+
+	// a second lock waits until the first is released
+	locked := make(chan error)
+	go func() {
+		locked <- newFileLock(fs).lock()
+	}()
+	select {
+	case err := <-locked:
+		t.Fatalf("second lock() = %v while the first is held", err)
+	case <-time.After(20 * lockRetry):
+	}
+	if err := l.unlock(); err != nil {
+		t.Fatalf("unlock() error = %v", err)
+	}
+	if err := <-locked; err != nil {
+		t.Fatalf("second lock() error = %v", err)
+	}
+
+	// a lock left behind by a process that died is taken over
+	old := time.Now().Add(-2 * staleLockAge)
+	_ = fs.Chtimes(dbLockFile, old, old)
+	if err := l.lock(); err != nil {
+		t.Errorf("lock() of a stale lock error = %v", err)
+	}
+
+	// a waiter that found the stale lock after another took it over leaves the new lock alone
+	if removed, err := newFileLock(fs).takeOver(old); removed || err != nil {
+		t.Errorf("takeOver() of a lock taken since = %v, %v, want false", removed, err)
+	}
+	if exists, _ := afero.DirExists(fs, dbLockFile); !exists {
+		t.Fatalf("takeOver() removed a lock taken since")
+	}
+	_ = l.unlock()
+
+	// a guard left behind by a waiter that died taking over does not keep the stale lock forever
+	_ = l.lock()
+	_ = fs.Chtimes(dbLockFile, old, old)
+	guard := fmt.Sprintf("%s.%d", dbLockFile, old.UnixNano())
+	_ = fs.Mkdir(guard, 0777)
+	_ = fs.Chtimes(guard, old, old)
+	if err := newFileLock(fs).lock(); err != nil {
+		t.Errorf("lock() of a stale lock with a stale guard error = %v", err)
+	}
+	if exists, _ := afero.DirExists(fs, guard); exists {
+		t.Errorf("the guard %s is left behind", guard)
+	}
+}
+
+// stressBoard is a board no other writer of the stress test saves, every second one has a constraint
+func stressBoard(writer, n int) *board.SudokuBoard {
+	b := board.FromNumbers([9][9]int{{writer + 1}, {}, {}, {}, {0, 0, 0, 0, n%9 + 1}, {}, {}, {}, {0, 0, 0, 0, 0, 0, 0, 0, n/9 + 1}})
+	if n%2 == 1 {
+		b = b.AddConstraints(board.AntiKingConstraint{})
+	}
+	return b
+}
+
+func Test_sudokuBoardFileRepo_ConcurrentWriters(t *testing.T) {
+	const writers = 8
+	const boardsEach = 20
+
+	fs := afero.NewMemMapFs()
+	// every second writer uses a repository of its own like a separate process would
+	repos := make([]SudokuBoardRepo, 2)
+	for i := range repos {
+		s, sd, err := NewSudokuBoardRepoUsingFs(fs)
+		if err != nil {
+			t.Fatalf("NewSudokuBoardRepoUsingFs() error = %v", err)
+		}
+		defer sd()
+		repos[i] = s
+	}
+
+	var wg sync.WaitGroup
+	ids := make([][]int, writers)
+	errs := make(chan error, writers*boardsEach)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			s := repos[w%len(repos)]
+			for n := 0; n < boardsEach; n++ {
+				id, err := s.SaveNew(stressBoard(w, n))
+				if err != nil {
+					errs <- err
+					return
+				}
+				ids[w] = append(ids[w], id)
+				// reading while others write must see whole boards
+				if _, err := s.List(0, id+1); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("concurrent SaveNew() error = %v", err)
+	}
+
+	for _, s := range repos {
+		if count, err := s.Count(); count != writers*boardsEach || err != nil {
+			t.Errorf("Count() = %d, %v, want %d", count, err, writers*boardsEach)
+		}
+		for w := range ids {
+			for n, id := range ids[w] {
+				want := stressBoard(w, n)
+				if _, got, err := s.GetByNumber(id); err != nil || !reflect.DeepEqual(got, want) {
+					t.Errorf("GetByNumber(%d) = \n%v %v, %v, want \n%v %v", id, got, got.Constraints(), err, want, want.Constraints())
+				}
+			}
+		}
+	}
+	if exists, _ := afero.DirExists(fs, dbLockFile); exists {
+		t.Errorf("%s is left behind", dbLockFile)
+	}
+}
+
+func Test_sudokuBoardFileRepo_Locked(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s, sd, _ := NewSudokuBoardRepoUsingFs(fs)
+	defer sd()
+
+	l := newFileLock(fs)
+	_ = l.lock()
+	done := make(chan error)
+	go func() {
+		_, err := s.SaveNew(board.FromNumbers([9][9]int{{1}}))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("SaveNew() = %v while another process holds the lock", err)
+	case <-time.After(20 * lockRetry):
+	}
+	_ = l.unlock()
+	if err := <-done; err != nil {
+		t.Errorf("SaveNew() error = %v", err)
+	}
+}
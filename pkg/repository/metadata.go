@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"slices"
+	"time"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+const (
+	boardMetadataBytes = 48
+	boardSourceBytes   = 24
+	boardChecksumBytes = 4
+	boardRecordBytes   = boardMetadataBytes + 2*boardDataBytes + boardChecksumBytes
+	// boardFlagUnique marks boards with a single solution, only those have a solution and a rating
+	boardFlagUnique = 1 << 0
+	// boardFlagRemoved marks the record of a board that was removed, the record is kept so later ids do not change
+	boardFlagRemoved = 1 << 1
+)
+
+// strategyBits gives each strategy its bit in the record, new strategies must be added at the end
+var strategyBits = []solver.StrategyName{
+	solver.StrategyNameLastInRowStrategy,
+	solver.StrategyNameLastInColumnStrategy,
+	solver.StrategyNameLastInRegionStrategy,
+	solver.StrategyNameLastCandidateStrategy,
+	solver.StrategyNameThermometerStrategy,
+	solver.StrategyNameArrowStrategy,
+	solver.StrategyNameSandwichStrategy,
+	solver.StrategyNameLockedCandidatesStrategy,
+	solver.StrategyNameNakedPairStrategy,
+	solver.StrategyNameXWingStrategy,
+	solver.StrategyNamePsychicStrategy,
+}
+
+/*
+Metadata is what the repository knows about a board besides its numbers. The solution and the rating are only known
+for boards with a single solution, Rating.Strategies is then sorted in the order of strategyBits rather than the order
+the strategies were needed in. Hash is the canonical hash of the board, see board.CanonicalHash. It is 0 for boards
+with constraints as those have none and for boards breaking the rules.
+*/
+type Metadata struct {
+	Unique  bool          `json:"unique"`
+	Rating  solver.Rating `json:"rating"`
+	Clues   int           `json:"clues"`
+	Source  string        `json:"source,omitempty"`
+	Created time.Time     `json:"created"`
+	Hash    uint64        `json:"hash,omitempty"`
+}
+
+/*
+describe counts the clues of the board, then solves, rates and hashes it. Boards with several solutions or none keep
+an empty solution and are not rated. The board itself is not modified.
+*/
+func describe(b *board.SudokuBoard, source string, created time.Time) (Metadata, *board.SudokuBoard) {
+	m := Metadata{Source: source, Created: created}
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			if b.GetAt(x, y) != 0 {
+				m.Clues++
+			}
+		}
+	}
+
+	solution := board.FromNumbers([9][9]int{})
+	if solutions := solver.FindSolutions(b, 2); len(solutions) == 1 {
+		m.Unique = true
+		m.Rating = solver.Rate(b)
+		solution = solutions[0]
+	}
+	if !board.VerifyBoard(b) {
+		return m, solution
+	}
+	if hash, err := board.CanonicalHash(b); err == nil {
+		m.Hash = hash
+	}
+	return m, solution
+}
+
+/*
+boardFormat holds the records of boards.bin, all numbers are little endian:
+
+	offset  size  field
+	0       1     flags, see boardFlagUnique
+	1       1     difficulty
+	2       1     number of clues
+	3       1     reserved, always 0
+	4       4     strategies used, a bit for each of strategyBits
+	8       8     creation time in unix seconds, 0 if unknown
+	16      8     canonical hash
+	24      24    source, padded with 0 bytes
+	48      41    the board, see board.SudokuBoard.MarshalBinary
+	89      41    the solution, all blank if unknown
+	130     4     CRC-32 (IEEE) of the record before it, see sealRecord
+
+The metadata comes first so it can be read without the boards. Version 1 records only held the board, the rest is
+filled in when the file is upgraded. Version 2 records had no checksum, it is added when the file is upgraded.
+*/
+var boardFormat = recordFormat{
+	magic:      [4]byte{'S', 'D', 'K', 'B'},
+	version:    3,
+	recordSize: boardRecordBytes,
+}
+
+// sealRecord appends the checksum to a record that has every field but it
+func sealRecord(record []byte) []byte {
+	return binary.LittleEndian.AppendUint32(record, crc32.ChecksumIEEE(record))
+}
+
+// checkRecord compares the checksum of record n with its fields
+func checkRecord(n int, record []byte) error {
+	fields := record[:boardRecordBytes-boardChecksumBytes]
+	if crc32.ChecksumIEEE(fields) != binary.LittleEndian.Uint32(record[len(fields):]) {
+		return fmt.Errorf("board %d: %w: checksum does not match", n, ErrCorrupt)
+	}
+	return nil
+}
+
+// removedRecord is the tombstone left in place of a removed board, it is only taken out by compacting the repository
+func removedRecord() []byte {
+	record := make([]byte, boardRecordBytes-boardChecksumBytes)
+	record[0] = boardFlagRemoved
+	return sealRecord(record)
+}
+
+func isRemoved(record []byte) bool {
+	return record[0]&boardFlagRemoved != 0
+}
+
+// strategyMask sets the bit of each of the strategies, see strategyBits
+func strategyMask(names []solver.StrategyName) (uint32, error) {
+	var mask uint32
+	for _, name := range names {
+		bit := slices.Index(strategyBits, name)
+		if bit == -1 {
+			return 0, fmt.Errorf("strategy %q has no bit", name)
+		}
+		mask |= 1 << bit
+	}
+	return mask, nil
+}
+
+func encodeMetadata(m Metadata) ([]byte, error) {
+	if len(m.Source) > boardSourceBytes {
+		return nil, fmt.Errorf("source %q is longer than %d bytes", m.Source, boardSourceBytes)
+	}
+	if m.Clues < 0 || m.Clues > 81 {
+		return nil, fmt.Errorf("%d clues", m.Clues)
+	}
+
+	data := make([]byte, boardMetadataBytes)
+	if m.Unique {
+		data[0] |= boardFlagUnique
+	}
+	data[1] = byte(m.Rating.Difficulty)
+	data[2] = byte(m.Clues)
+
+	strategies, err := strategyMask(m.Rating.Strategies)
+	if err != nil {
+		return nil, err
+	}
+	binary.LittleEndian.PutUint32(data[4:], strategies)
+
+	if !m.Created.IsZero() {
+		binary.LittleEndian.PutUint64(data[8:], uint64(m.Created.Unix()))
+	}
+	binary.LittleEndian.PutUint64(data[16:], m.Hash)
+	copy(data[24:], m.Source)
+	return data, nil
+}
+
+func decodeMetadata(data []byte) Metadata {
+	m := Metadata{
+		Unique: data[0]&boardFlagUnique != 0,
+		Clues:  int(data[2]),
+		Hash:   binary.LittleEndian.Uint64(data[16:]),
+	}
+	if m.Unique {
+		m.Rating.Difficulty = solver.StrategyDifficulty(data[1])
+		strategies := binary.LittleEndian.Uint32(data[4:])
+		for i, name := range strategyBits {
+			if strategies&(1<<i) != 0 {
+				m.Rating.Strategies = append(m.Rating.Strategies, name)
+			}
+		}
+	}
+	if created := int64(binary.LittleEndian.Uint64(data[8:])); created != 0 {
+		m.Created = time.Unix(created, 0).UTC()
+	}
+
+	source := data[24:boardMetadataBytes]
+	for len(source) > 0 && source[len(source)-1] == 0 {
+		source = source[:len(source)-1]
+	}
+	m.Source = string(source)
+	return m
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/solver"
+)
+
+func Test_encodeMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata Metadata
+	}{
+		{name: "blank"},
+		{
+			name: "rated",
+			metadata: Metadata{
+				Unique: true,
+				Rating: solver.Rating{
+					Difficulty: solver.StrategyDifficultyHard,
+					Strategies: []solver.StrategyName{solver.StrategyNameLastInRowStrategy, solver.StrategyNameXWingStrategy},
+				},
+				Clues:   24,
+				Source:  "nyt/hard",
+				Created: time.Date(2024, 5, 17, 8, 30, 0, 0, time.UTC),
+				Hash:    0xfedcba9876543210,
+			},
+		},
+		{name: "longest source", metadata: Metadata{Source: "012345678901234567890123"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := encodeMetadata(tt.metadata)
+			if err != nil {
+				t.Fatalf("encodeMetadata() error = %v", err)
+			}
+			if len(data) != boardMetadataBytes {
+				t.Errorf("encodeMetadata() = %d bytes, want %d", len(data), boardMetadataBytes)
+			}
+			if got := decodeMetadata(data); !reflect.DeepEqual(got, tt.metadata) {
+				t.Errorf("decodeMetadata() = %+v, want %+v", got, tt.metadata)
+			}
+		})
+	}
+
+	invalid := []Metadata{
+		{Source: "0123456789012345678901234"},
+		{Clues: 82},
+		{Rating: solver.Rating{Strategies: []solver.StrategyName{"Unknown"}}},
+	}
+	for _, m := range invalid {
+		if _, err := encodeMetadata(m); err == nil {
+			t.Errorf("encodeMetadata(%+v) did not fail", m)
+		}
+	}
+}
+
+func Test_describe(t *testing.T) {
+	solution, _ := board.ParseLine(
+		"534678912672195348198342567859761423426853791713924856961537284287419635345286179")
+	puzzle, _ := board.ParseLine(
+		"53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79")
+	created := time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC)
+
+	m, got := describe(puzzle, "test", created)
+	if !m.Unique || m.Clues != 30 || m.Source != "test" || m.Created != created || m.Hash == 0 {
+		t.Errorf("describe() = %+v", m)
+	}
+	if !reflect.DeepEqual(m.Rating, solver.Rate(puzzle)) {
+		t.Errorf("describe() rating = %+v, want %+v", m.Rating, solver.Rate(puzzle))
+	}
+	if !reflect.DeepEqual(got, solution) {
+		t.Errorf("describe() solution = \n%v, want \n%v", got, solution)
+	}
+	if hash, _ := board.CanonicalHash(puzzle); m.Hash != hash {
+		t.Errorf("describe() hash = %x, want %x", m.Hash, hash)
+	}
+
+	// the empty board has many solutions
+	m, got = describe(board.FromNumbers([9][9]int{}), "", time.Time{})
+	if m.Unique || m.Clues != 0 || m.Rating.Strategies != nil {
+		t.Errorf("describe() of the empty board = %+v", m)
+	}
+	if !reflect.DeepEqual(got, board.FromNumbers([9][9]int{})) {
+		t.Errorf("describe() of the empty board has solution \n%v", got)
+	}
+
+	variant := puzzle.Copy().AddConstraints(board.AntiKnightConstraint{})
+	if m, _ := describe(variant, "", time.Time{}); m.Hash != 0 {
+		t.Errorf("describe() of a variant has hash %x", m.Hash)
+	}
+}
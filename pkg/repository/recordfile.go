@@ -0,0 +1,421 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	osConst "os"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	recordHeaderBytes = 32
+	// recordHeaderCountOffset is where the number of records is in the header
+	recordHeaderCountOffset = 16
+	// recordFlagsKnown holds every flag this version understands, files with other flags are refused
+	recordFlagsKnown = 0
+	// rawVersion is the version of the records in files written before the header existed
+	rawVersion = 1
+)
+
+/*
+recordFormat describes the records of a kind of file. The version is raised whenever the layout of the records
+changes, files of an older version listed in older are upgraded when they are opened, see upgradeFile.
+*/
+type recordFormat struct {
+	magic      [4]byte
+	version    uint16
+	recordSize int
+	older      map[uint16]recordUpgrade
+}
+
+// recordUpgrade reads the records of an older version, upgrade turns record n into one of the current version
+type recordUpgrade struct {
+	recordSize int
+	upgrade    func(n int, record []byte) []byte
+}
+
+var candidateFormat = recordFormat{
+	magic:      [4]byte{'S', 'D', 'K', 'C'},
+	version:    1,
+	recordSize: candidateDataBytes,
+}
+
+/*
+recordHeader starts every record file, all numbers are little endian:
+
+	offset  size  field
+	0       4     magic, tells what kind of records the file holds
+	4       2     version of the layout
+	6       2     size of the header, the records start right after it
+	8       4     size of each record
+	12      4     flags, unknown flags make the file unreadable
+	16      8     number of records
+	24      8     generation, raised whenever records are rewritten rather than added
+
+The count is only raised once the records are written, so records after it are the remains of a write that did not
+finish. They are ignored, and removed when the board repository is opened, see recordFile.truncateTail. Together the
+count and the generation tell another process whether the records it read are still current, see recordFile.behind.
+Files written before the generation existed have 0 there.
+*/
+type recordHeader struct {
+	Magic      [4]byte
+	Version    uint16
+	HeaderSize uint16
+	RecordSize uint32
+	Flags      uint32
+	Count      uint64
+	Generation uint64
+}
+
+func (h recordHeader) marshal() []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, h)
+	return buf.Bytes()
+}
+
+// check compares the header with what the repository expects of a file of the given version
+func (h recordHeader) check(format recordFormat) error {
+	recordSize := format.recordSize
+	if h.Version != format.version {
+		recordSize = format.older[h.Version].recordSize
+	}
+	switch {
+	case h.Magic != format.magic:
+		return fmt.Errorf("%w: magic %q, expected %q", ErrCorrupt, h.Magic[:], format.magic[:])
+	case recordSize == 0:
+		return fmt.Errorf("version %d is not supported, at most %d is", h.Version, format.version)
+	case h.HeaderSize < recordHeaderBytes:
+		return fmt.Errorf("%w: header of %d bytes is too short", ErrCorrupt, h.HeaderSize)
+	case h.RecordSize != uint32(recordSize):
+		return fmt.Errorf("%w: records of %d bytes, expected %d", ErrCorrupt, h.RecordSize, recordSize)
+	case h.Flags&^recordFlagsKnown != 0:
+		return fmt.Errorf("unknown flags %#x", h.Flags&^recordFlagsKnown)
+	}
+	return nil
+}
+
+func newRecordHeader(format recordFormat, count int) recordHeader {
+	return recordHeader{
+		Magic:      format.magic,
+		Version:    format.version,
+		HeaderSize: recordHeaderBytes,
+		RecordSize: uint32(format.recordSize),
+		Count:      uint64(count),
+	}
+}
+
+/*
+openFile opens or creates the file for reading and writing. If the file system does not allow that, such as a file
+system wrapped in afero.NewReadOnlyFs, the file is opened for reading only and readOnly is true. A file that does not
+exist can not be opened read only.
+*/
+func openFile(fs afero.Fs, name string) (file afero.File, readOnly bool, err error) {
+	file, err = fs.OpenFile(name, osConst.O_CREATE|osConst.O_RDWR, 0666)
+	if errors.Is(err, osConst.ErrPermission) {
+		file, err = fs.OpenFile(name, osConst.O_RDONLY, 0)
+		readOnly = true
+	}
+	return file, readOnly, err
+}
+
+/*
+recordFile is a file of fixed size records behind a recordHeader, the index of a record is its id. Files written
+before the header existed and files of an older version are upgraded when they are opened, see upgradeFile.
+*/
+type recordFile struct {
+	name     string
+	file     afero.File
+	readOnly bool
+	format   recordFormat
+	header   recordHeader
+}
+
+/*
+openRecordFile opens or creates the record file, upgrading it first if it is not of the current version. A file that
+can only be read is not upgraded, opening it fails with ErrReadOnly instead.
+*/
+func openRecordFile(fs afero.Fs, name string, format recordFormat) (*recordFile, error) {
+	file, readOnly, err := openFile(fs, name)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	f := &recordFile{name: name, file: file, readOnly: readOnly, format: format}
+	if stat.Size() == 0 {
+		f.header = newRecordHeader(format, 0)
+		if readOnly {
+			return f, nil
+		}
+		if _, err := file.WriteAt(f.header.marshal(), 0); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		return f, nil
+	}
+
+	start := make([]byte, len(format.magic))
+	if _, err := file.ReadAt(start, 0); err != nil && err != io.EOF {
+		_ = file.Close()
+		return nil, err
+	}
+	if !bytes.Equal(start, format.magic[:]) {
+		_ = file.Close()
+		if readOnly {
+			return nil, fmt.Errorf("%s: has no header and needs migrating: %w", name, ErrReadOnly)
+		}
+		// without the header the records are those of the first version
+		raw := recordHeader{Version: rawVersion, RecordSize: uint32(format.recordSize)}
+		if format.version != rawVersion {
+			raw.RecordSize = uint32(format.older[rawVersion].recordSize)
+		}
+		raw.Count = uint64(stat.Size()) / uint64(raw.RecordSize)
+		if err := upgradeFile(fs, name, format, raw); err != nil {
+			return nil, fmt.Errorf("%s: migrating: %w", name, err)
+		}
+		return openRecordFile(fs, name, format)
+	}
+
+	if err := f.readHeader(stat.Size(), format); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if f.header.Version != format.version {
+		_ = file.Close()
+		if readOnly {
+			return nil, fmt.Errorf("%s: version %d needs upgrading: %w", name, f.header.Version, ErrReadOnly)
+		}
+		if err := upgradeFile(fs, name, format, f.header); err != nil {
+			return nil, fmt.Errorf("%s: upgrading from version %d: %w", name, f.header.Version, err)
+		}
+		return openRecordFile(fs, name, format)
+	}
+	return f, nil
+}
+
+func (f *recordFile) readHeader(size int64, format recordFormat) error {
+	data := make([]byte, recordHeaderBytes)
+	if _, err := f.file.ReadAt(data, 0); err == io.EOF {
+		return fmt.Errorf("%w: header is cut short", ErrCorrupt)
+	} else if err != nil {
+		return fmt.Errorf("reading header: %w", err)
+	}
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &f.header); err != nil {
+		return err
+	}
+	if err := f.header.check(format); err != nil {
+		return err
+	}
+	if end := f.offset(f.count()); end > size {
+		return fmt.Errorf("%w: %d records need %d bytes, the file has %d", ErrCorrupt, f.count(), end, size)
+	}
+	return nil
+}
+
+/*
+upgradeFile rewrites the records described by the header, which is that of an older version, as records of the
+current version. A file without a header, as written before the header was added, has a HeaderSize of 0 and any
+partial record at its end is dropped. The new file is written next to the old one and renamed over it, so the old
+file is left as it was if the upgrade fails.
+*/
+func upgradeFile(fs afero.Fs, name string, format recordFormat, header recordHeader) error {
+	upgrade := format.older[header.Version].upgrade
+	if header.Version == format.version {
+		upgrade = nil
+	} else if upgrade == nil {
+		return fmt.Errorf("version %d can not be upgraded", header.Version)
+	}
+
+	old, err := afero.ReadFile(fs, name)
+	if err != nil {
+		return err
+	}
+	count := int(header.Count)
+	content := newRecordHeader(format, count).marshal()
+	for n := 0; n < count; n++ {
+		start := int(header.HeaderSize) + n*int(header.RecordSize)
+		end := start + int(header.RecordSize)
+		record := old[start:end:end]
+		if upgrade != nil {
+			record = upgrade(n, record)
+		}
+		content = append(content, record...)
+	}
+
+	upgraded := name + ".migrate"
+	if err := afero.WriteFile(fs, upgraded, content, 0666); err != nil {
+		return err
+	}
+	return fs.Rename(upgraded, name)
+}
+
+/*
+behind checks whether the count or the generation in the file differ from those read with the header, which happens
+when another process saved, changed or removed records since. They are read on their own without a lock, so a header
+that is being written may be seen as changed when it is not.
+*/
+func (f *recordFile) behind() (bool, error) {
+	data := make([]byte, 16)
+	if _, err := f.file.ReadAt(data, recordHeaderCountOffset); err != nil {
+		return false, fmt.Errorf("reading header: %w", err)
+	}
+	return binary.LittleEndian.Uint64(data) != f.header.Count ||
+		binary.LittleEndian.Uint64(data[8:]) != f.header.Generation, nil
+}
+
+// refresh reads the header again to see the records other processes saved since it was read
+func (f *recordFile) refresh() error {
+	stat, err := f.file.Stat()
+	if err != nil {
+		return err
+	}
+	header := f.header
+	if err := f.readHeader(stat.Size(), f.format); err != nil {
+		f.header = header
+		return err
+	}
+	if version := f.header.Version; version != f.format.version {
+		f.header = header
+		return fmt.Errorf("%w: version changed to %d while open", ErrCorrupt, version)
+	}
+	return nil
+}
+
+func (f *recordFile) count() int {
+	return int(f.header.Count)
+}
+
+func (f *recordFile) offset(n int) int64 {
+	return int64(f.header.HeaderSize) + int64(n)*int64(f.header.RecordSize)
+}
+
+// read returns record n, which must be below count
+func (f *recordFile) read(n int) ([]byte, error) {
+	return f.readPart(n, 0, int(f.header.RecordSize))
+}
+
+// readRange returns the n records starting at first, which must all be below count
+func (f *recordFile) readRange(first, n int) ([]byte, error) {
+	if first < 0 || n < 0 || first+n > f.count() {
+		return nil, fmt.Errorf("records [%d,%d) are outside of [0,%d)", first, first+n, f.count())
+	}
+	data := make([]byte, n*int(f.header.RecordSize))
+	if _, err := f.file.ReadAt(data, f.offset(first)); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readPart returns size bytes of record n starting at start, so a field can be read without reading the record
+func (f *recordFile) readPart(n, start, size int) ([]byte, error) {
+	if n < 0 || n >= f.count() {
+		return nil, fmt.Errorf("record %d is outside of [0,%d)", n, f.count())
+	}
+	data := make([]byte, size)
+	if _, err := f.file.ReadAt(data, f.offset(n)+int64(start)); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// append writes whole records after the last one and returns the index of the first, the count is raised last
+func (f *recordFile) append(data []byte) (int, error) {
+	writes, header, err := f.appendWrites(data)
+	if err != nil {
+		return 0, err
+	}
+	for _, w := range writes {
+		if _, err := f.file.WriteAt(w.data, w.offset); err != nil {
+			return 0, err
+		}
+	}
+	first := f.count()
+	f.header = header
+	return first, nil
+}
+
+/*
+appendWrites returns the writes that add whole records after the last one, without making them, and the header they
+leave the file with. The header comes last so the count is only raised once the records are written. Once the writes
+are made the header has to be set to the one returned, see append and journal.
+*/
+func (f *recordFile) appendWrites(data []byte) ([]journalWrite, recordHeader, error) {
+	if f.readOnly {
+		return nil, recordHeader{}, ErrReadOnly
+	}
+	if len(data)%int(f.header.RecordSize) != 0 {
+		return nil, recordHeader{}, fmt.Errorf("%d bytes are not whole records of %d bytes", len(data), f.header.RecordSize)
+	}
+	header := f.header
+	header.Count += uint64(len(data) / int(f.header.RecordSize))
+	return []journalWrite{
+		{name: f.name, offset: f.offset(f.count()), data: data},
+		{name: f.name, offset: 0, data: header.marshal()},
+	}, header, nil
+}
+
+// recordWrite returns the write replacing record n, which must be below count, without making it
+func (f *recordFile) recordWrite(n int, record []byte) journalWrite {
+	return journalWrite{name: f.name, offset: f.offset(n), data: record}
+}
+
+/*
+generationWrite returns the write raising the generation of the header, without making it, and the header it leaves
+the file with. It has to go with every set of recordWrite so other processes see the records changed, see behind.
+*/
+func (f *recordFile) generationWrite() (journalWrite, recordHeader) {
+	header := f.header
+	header.Generation++
+	return journalWrite{name: f.name, offset: 0, data: header.marshal()}, header
+}
+
+/*
+rewriteWrites returns the write replacing every record of the file with the whole records of data, without making it,
+and the header it leaves the file with. Once the write is made the header has to be set to the one returned.
+*/
+func (f *recordFile) rewriteWrites(data []byte) ([]journalWrite, recordHeader, error) {
+	if f.readOnly {
+		return nil, recordHeader{}, ErrReadOnly
+	}
+	if len(data)%int(f.header.RecordSize) != 0 {
+		return nil, recordHeader{}, fmt.Errorf("%d bytes are not whole records of %d bytes", len(data), f.header.RecordSize)
+	}
+	header := newRecordHeader(f.format, len(data)/int(f.header.RecordSize))
+	header.Generation = f.header.Generation + 1
+	content := append(header.marshal(), data...)
+	return []journalWrite{{name: f.name, offset: 0, data: content, truncate: true}}, header, nil
+}
+
+// truncateTail removes what a write that did not finish left after the last record, a read only file is left as it is
+func (f *recordFile) truncateTail() error {
+	if f.readOnly {
+		return nil
+	}
+	stat, err := f.file.Stat()
+	if err != nil {
+		return err
+	}
+	if end := f.offset(f.count()); stat.Size() > end {
+		return f.file.Truncate(end)
+	}
+	return nil
+}
+
+func (f *recordFile) close() error {
+	if f.readOnly {
+		return f.file.Close()
+	}
+	if err := f.file.Sync(); err != nil {
+		_ = f.file.Close()
+		return err
+	}
+	return f.file.Close()
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+const testRecordFile = "records.bin"
+
+var testFormat = recordFormat{magic: [4]byte{'T', 'E', 'S', 'T'}, version: 1, recordSize: 2}
+
+func writeTestFile(fs afero.Fs, content []byte) {
+	_ = afero.WriteFile(fs, testRecordFile, content, 0666)
+}
+
+func readTestFile(fs afero.Fs) []byte {
+	content, _ := afero.ReadFile(fs, testRecordFile)
+	return content
+}
+
+func Test_recordFile_Header(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	f, err := openRecordFile(fs, testRecordFile, recordFormat{magic: testFormat.magic, version: 1, recordSize: 3})
+	if err != nil {
+		t.Fatalf("openRecordFile() error = %v", err)
+	}
+	if _, err := f.append([]byte{1, 2, 3, 4, 5, 6}); err != nil {
+		t.Fatalf("append() error = %v", err)
+	}
+	_ = f.close()
+
+	want := []byte{
+		'T', 'E', 'S', 'T', // magic
+		1, 0, // version
+		32, 0, // header size
+		3, 0, 0, 0, // record size
+		0, 0, 0, 0, // flags
+		2, 0, 0, 0, 0, 0, 0, 0, // count
+		0, 0, 0, 0, 0, 0, 0, 0, // generation
+		1, 2, 3, 4, 5, 6,
+	}
+	if got := readTestFile(fs); !reflect.DeepEqual(got, want) {
+		t.Errorf("file = %v, want %v", got, want)
+	}
+}
+
+func Test_recordFile_Reopen(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	f, _ := openRecordFile(fs, testRecordFile, testFormat)
+	_, _ = f.append([]byte{1, 2})
+	first, _ := f.append([]byte{3, 4, 5, 6})
+	_ = f.close()
+	if first != 1 {
+		t.Errorf("append() = %d, want 1", first)
+	}
+
+	f, err := openRecordFile(fs, testRecordFile, testFormat)
+	if err != nil {
+		t.Fatalf("openRecordFile() error = %v", err)
+	}
+	defer f.close()
+	if f.count() != 3 {
+		t.Errorf("count() = %d, want 3", f.count())
+	}
+	for n, want := range [][]byte{{1, 2}, {3, 4}, {5, 6}} {
+		if got, err := f.read(n); err != nil || !bytes.Equal(got, want) {
+			t.Errorf("read(%d) = %v, %v, want %v", n, got, err, want)
+		}
+	}
+	if _, err := f.read(3); err == nil {
+		t.Errorf("read(3) did not fail")
+	}
+}
+
+func Test_recordFile_Migrate(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	// two records and half of a third
+	writeTestFile(fs, []byte{1, 2, 3, 4, 5})
+
+	f, err := openRecordFile(fs, testRecordFile, testFormat)
+	if err != nil {
+		t.Fatalf("openRecordFile() error = %v", err)
+	}
+	if f.count() != 2 {
+		t.Errorf("count() = %d, want 2", f.count())
+	}
+	_, _ = f.append([]byte{7, 8})
+	_ = f.close()
+
+	content := readTestFile(fs)
+	if !bytes.Equal(content[:4], testFormat.magic[:]) ||
+		!bytes.Equal(content[recordHeaderBytes:], []byte{1, 2, 3, 4, 7, 8}) {
+		t.Errorf("file = %v after migrating", content)
+	}
+	if exists, _ := afero.Exists(fs, testRecordFile+".migrate"); exists {
+		t.Errorf("migration file is left behind")
+	}
+}
+
+func Test_recordFile_UnfinishedWrite(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	f, _ := openRecordFile(fs, testRecordFile, testFormat)
+	_, _ = f.append([]byte{1, 2})
+	_ = f.close()
+	// records written without raising the count
+	writeTestFile(fs, append(readTestFile(fs), 3, 4, 5))
+
+	f, err := openRecordFile(fs, testRecordFile, testFormat)
+	if err != nil {
+		t.Fatalf("openRecordFile() error = %v", err)
+	}
+	if f.count() != 1 {
+		t.Errorf("count() = %d, want 1", f.count())
+	}
+	if first, _ := f.append([]byte{9, 9}); first != 1 {
+		t.Errorf("append() = %d, want 1", first)
+	}
+	got, _ := f.read(1)
+	_ = f.close()
+	if !bytes.Equal(got, []byte{9, 9}) {
+		t.Errorf("read(1) = %v, want the record written over the unfinished one", got)
+	}
+}
+
+func Test_recordFile_Invalid(t *testing.T) {
+	valid := recordHeader{Magic: testFormat.magic, Version: 1, HeaderSize: 32, RecordSize: 2, Count: 1}
+	tests := []struct {
+		name   string
+		change func(h *recordHeader)
+		extra  []byte
+	}{
+		{name: "newer version", change: func(h *recordHeader) { h.Version = testFormat.version + 1 }, extra: []byte{1, 2}},
+		{name: "record size", change: func(h *recordHeader) { h.RecordSize = 3 }, extra: []byte{1, 2, 3}},
+		{name: "unknown flag", change: func(h *recordHeader) { h.Flags = 1 << 31 }, extra: []byte{1, 2}},
+		{name: "short header", change: func(h *recordHeader) { h.HeaderSize = 8 }, extra: []byte{1, 2}},
+		{name: "truncated", change: func(h *recordHeader) { h.Count = 2 }, extra: []byte{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := valid
+			tt.change(&header)
+			fs := afero.NewMemMapFs()
+			writeTestFile(fs, append(header.marshal(), tt.extra...))
+			if f, err := openRecordFile(fs, testRecordFile, testFormat); err == nil {
+				_ = f.close()
+				t.Errorf("openRecordFile() did not fail")
+			}
+		})
+	}
+}
+
+func Test_recordFile_Upgrade(t *testing.T) {
+	upgraded := recordFormat{
+		magic:      testFormat.magic,
+		version:    2,
+		recordSize: 3,
+		older: map[uint16]recordUpgrade{1: {recordSize: 2, upgrade: func(n int, record []byte) []byte {
+			return append(record, byte(n))
+		}}},
+	}
+	tests := []struct {
+		name    string
+		initial func(fs afero.Fs)
+	}{
+		{name: "raw file", initial: func(fs afero.Fs) { writeTestFile(fs, []byte{1, 2, 3, 4, 5}) }},
+		{name: "version 1", initial: func(fs afero.Fs) {
+			f, _ := openRecordFile(fs, testRecordFile, testFormat)
+			_, _ = f.append([]byte{1, 2, 3, 4})
+			_ = f.close()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			tt.initial(fs)
+
+			f, err := openRecordFile(fs, testRecordFile, upgraded)
+			if err != nil {
+				t.Fatalf("openRecordFile() error = %v", err)
+			}
+			_ = f.close()
+			want := append(newRecordHeader(upgraded, 2).marshal(), 1, 2, 0, 3, 4, 1)
+			if got := readTestFile(fs); !reflect.DeepEqual(got, want) {
+				t.Errorf("file = %v, want %v", got, want)
+			}
+
+			// the upgraded file is of a version the old format does not know
+			if f, err := openRecordFile(fs, testRecordFile, testFormat); err == nil {
+				_ = f.close()
+				t.Errorf("openRecordFile() of a newer version did not fail")
+			}
+		})
+	}
+}
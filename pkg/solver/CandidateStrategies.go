@@ -0,0 +1,163 @@
+package solver
+
+import "droidkfx.com/sudoku/pkg/board"
+
+// units lists the cells of every row, column and region, in that order
+var units = func() [27][9]board.Cell {
+	var result [27][9]board.Cell
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			result[i][j] = board.Cell{X: j, Y: i}
+			result[9+i][j] = board.Cell{X: i, Y: j}
+			result[18+i][j] = board.Cell{X: (i%3)*3 + j%3, Y: (i/3)*3 + j/3}
+		}
+	}
+	return result
+}()
+
+/*
+LockedCandidatesStrategy looks for a number that can only go in the part of a region that overlaps a row or column,
+or in the part of a row or column that overlaps a region. The number is then removed from the rest of the other unit.
+*/
+func LockedCandidatesStrategy(_ *board.SudokuBoard, opts *[9][9][9]bool) *StrategyStep {
+	for _, unit := range units {
+		for v := 0; v < 9; v++ {
+			var cells []board.Cell
+			for _, c := range unit {
+				if opts[c.X][c.Y][v] {
+					cells = append(cells, c)
+				}
+			}
+			if len(cells) < 2 {
+				continue
+			}
+			for _, other := range units {
+				if !containsAll(other, cells) || other == unit {
+					continue
+				}
+				var actions []StrategyAction
+				for _, c := range other {
+					if opts[c.X][c.Y][v] && !containsCell(unit, c) {
+						actions = append(actions, StrategyAction{set: true, opts: true, x: c.X, y: c.Y, value: v + 1})
+					}
+				}
+				if len(actions) > 0 {
+					return &StrategyStep{name: StrategyNameLockedCandidatesStrategy, actions: actions}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// NakedPairStrategy finds two cells of a unit that only allow the same two numbers and removes them from the rest
+func NakedPairStrategy(_ *board.SudokuBoard, opts *[9][9][9]bool) *StrategyStep {
+	for _, unit := range units {
+		for i, a := range unit {
+			if countOptions(opts, a) != 2 {
+				continue
+			}
+			for _, b := range unit[i+1:] {
+				if opts[a.X][a.Y] != opts[b.X][b.Y] {
+					continue
+				}
+				var actions []StrategyAction
+				for _, c := range unit {
+					if c == a || c == b {
+						continue
+					}
+					for v := 0; v < 9; v++ {
+						if opts[a.X][a.Y][v] && opts[c.X][c.Y][v] {
+							actions = append(actions, StrategyAction{set: true, opts: true, x: c.X, y: c.Y, value: v + 1})
+						}
+					}
+				}
+				if len(actions) > 0 {
+					return &StrategyStep{name: StrategyNameNakedPairStrategy, actions: actions}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+/*
+XWingStrategy finds a number that can only go in the same two columns of two rows. Each of the columns then holds
+it in one of those rows, so it is removed from the rest of both columns. The same is done with rows and columns
+swapped.
+*/
+func XWingStrategy(_ *board.SudokuBoard, opts *[9][9][9]bool) *StrategyStep {
+	for _, transposed := range []bool{false, true} {
+		at := func(line, pos, v int) bool {
+			if transposed {
+				return opts[line][pos][v]
+			}
+			return opts[pos][line][v]
+		}
+		for v := 0; v < 9; v++ {
+			var positions [9][]int
+			for line := 0; line < 9; line++ {
+				for pos := 0; pos < 9; pos++ {
+					if at(line, pos, v) {
+						positions[line] = append(positions[line], pos)
+					}
+				}
+			}
+			for a := 0; a < 9; a++ {
+				if len(positions[a]) != 2 {
+					continue
+				}
+				for b := a + 1; b < 9; b++ {
+					if len(positions[b]) != 2 || positions[a][0] != positions[b][0] || positions[a][1] != positions[b][1] {
+						continue
+					}
+					var actions []StrategyAction
+					for line := 0; line < 9; line++ {
+						for _, pos := range positions[a] {
+							if line == a || line == b || !at(line, pos, v) {
+								continue
+							}
+							x, y := pos, line
+							if transposed {
+								x, y = line, pos
+							}
+							actions = append(actions, StrategyAction{set: true, opts: true, x: x, y: y, value: v + 1})
+						}
+					}
+					if len(actions) > 0 {
+						return &StrategyStep{name: StrategyNameXWingStrategy, actions: actions}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func countOptions(opts *[9][9][9]bool, c board.Cell) int {
+	count := 0
+	for _, allowed := range opts[c.X][c.Y] {
+		if allowed {
+			count++
+		}
+	}
+	return count
+}
+
+func containsCell(unit [9]board.Cell, cell board.Cell) bool {
+	for _, c := range unit {
+		if c == cell {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(unit [9]board.Cell, cells []board.Cell) bool {
+	for _, c := range cells {
+		if !containsCell(unit, c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+package solver
+
+import (
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/board"
+)
+
+// removed returns the cells a step removes the value from
+func removed(step *StrategyStep, value int) map[board.Cell]bool {
+	result := map[board.Cell]bool{}
+	for _, a := range step.actions {
+		if a.set && a.opts && a.value == value {
+			result[board.Cell{X: a.x, Y: a.y}] = true
+		}
+	}
+	return result
+}
+
+func TestCandidateStrategies(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     StrategyMethod
+		opts  func(opts *[9][9][9]bool)
+		value int
+		want  []board.Cell
+	}{
+		{
+			name: "LockedCandidates pointing",
+			s:    LockedCandidatesStrategy,
+			// 1 can only be in the top row of the first region
+			opts: func(opts *[9][9][9]bool) {
+				for x := 0; x < 3; x++ {
+					opts[x][1][0], opts[x][2][0] = false, false
+				}
+			},
+			value: 1,
+			want:  []board.Cell{{X: 3, Y: 0}, {X: 4, Y: 0}, {X: 5, Y: 0}, {X: 6, Y: 0}, {X: 7, Y: 0}, {X: 8, Y: 0}},
+		},
+		{
+			name: "LockedCandidates claiming",
+			s:    LockedCandidatesStrategy,
+			// 1 can only be in the first region in the top row
+			opts: func(opts *[9][9][9]bool) {
+				for x := 3; x < 9; x++ {
+					opts[x][0][0] = false
+				}
+			},
+			value: 1,
+			want:  []board.Cell{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}},
+		},
+		{
+			name: "NakedPair",
+			s:    NakedPairStrategy,
+			opts: func(opts *[9][9][9]bool) {
+				for v := 2; v < 9; v++ {
+					opts[3][0][v], opts[7][0][v] = false, false
+				}
+			},
+			value: 2,
+			want: []board.Cell{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 4, Y: 0}, {X: 5, Y: 0}, {X: 6, Y: 0},
+				{X: 8, Y: 0}},
+		},
+		{
+			name: "XWing",
+			s:    XWingStrategy,
+			// 1 can only be in columns 0 and 4 of rows 0 and 5
+			opts: func(opts *[9][9][9]bool) {
+				for x := 0; x < 9; x++ {
+					if x != 0 && x != 4 {
+						opts[x][0][0], opts[x][5][0] = false, false
+					}
+				}
+			},
+			value: 1,
+			want: []board.Cell{{X: 0, Y: 1}, {X: 0, Y: 2}, {X: 0, Y: 3}, {X: 0, Y: 4}, {X: 0, Y: 6}, {X: 0, Y: 7},
+				{X: 0, Y: 8}, {X: 4, Y: 1}, {X: 4, Y: 2}, {X: 4, Y: 3}, {X: 4, Y: 4}, {X: 4, Y: 6}, {X: 4, Y: 7},
+				{X: 4, Y: 8}},
+		},
+		{
+			name: "XWing columns",
+			s:    XWingStrategy,
+			// 1 can only be in rows 2 and 7 of columns 1 and 6
+			opts: func(opts *[9][9][9]bool) {
+				for y := 0; y < 9; y++ {
+					if y != 2 && y != 7 {
+						opts[1][y][0], opts[6][y][0] = false, false
+					}
+				}
+			},
+			value: 1,
+			want: []board.Cell{{X: 0, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}, {X: 4, Y: 2}, {X: 5, Y: 2}, {X: 7, Y: 2},
+				{X: 8, Y: 2}, {X: 0, Y: 7}, {X: 2, Y: 7}, {X: 3, Y: 7}, {X: 4, Y: 7}, {X: 5, Y: 7}, {X: 7, Y: 7},
+				{X: 8, Y: 7}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &board.SudokuBoard{}
+			opts := GetPossibleValues(b)
+			tt.opts(&opts)
+			step := tt.s(b, &opts)
+			if step == nil {
+				t.Fatalf("no step found")
+			}
+			got := removed(step, tt.value)
+			if len(got) != len(tt.want) {
+				t.Errorf("step removes %v, want %v", step.actions, tt.want)
+			}
+			for _, c := range tt.want {
+				if !got[c] {
+					t.Errorf("step does not remove %d from %v", tt.value, c)
+				}
+			}
+		})
+	}
+}
+
+func TestCandidateStrategies_NothingFound(t *testing.T) {
+	b := &board.SudokuBoard{}
+	opts := GetPossibleValues(b)
+	for _, s := range []StrategyMethod{LockedCandidatesStrategy, NakedPairStrategy, XWingStrategy} {
+		if step := s(b, &opts); step != nil {
+			t.Errorf("%s found a step on an empty board", step.name)
+		}
+	}
+}
+
+func TestRate(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		difficulty StrategyDifficulty
+		uses       StrategyName
+	}{
+		{
+			name:       "singles",
+			line:       "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79",
+			difficulty: StrategyDifficultyEasy,
+			uses:       StrategyNameLastInRowStrategy,
+		},
+		{
+			name:       "x-wing",
+			line:       "100000569492056108056109240009640801064010000218035604040500016905061402621000005",
+			difficulty: StrategyDifficultyHard,
+			uses:       StrategyNameXWingStrategy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := board.ParseLine(tt.line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := Rate(b)
+			if got.Difficulty != tt.difficulty || !got.Uses(tt.uses) {
+				t.Errorf("Rate() = %v, want %s using %s", got, tt.difficulty, tt.uses)
+			}
+			if board.IsSolved(b) {
+				t.Errorf("Rate() changed the board")
+			}
+		})
+	}
+}
+
+func TestParseStrategyDifficulty(t *testing.T) {
+	for d := range strategyDifficultyNames {
+		if got, err := ParseStrategyDifficulty(d.String()); err != nil || got != d {
+			t.Errorf("ParseStrategyDifficulty(%q) = %v, %v", d.String(), got, err)
+		}
+	}
+	if _, err := ParseStrategyDifficulty("trivial"); err == nil {
+		t.Errorf("ParseStrategyDifficulty(\"trivial\") did not fail")
+	}
+}
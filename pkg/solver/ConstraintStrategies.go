@@ -0,0 +1,145 @@
+package solver
+
+import "droidkfx.com/sudoku/pkg/board"
+
+// ThermometerStrategy narrows every cell of a thermometer to the range left open by the cells before and after it
+func ThermometerStrategy(b *board.SudokuBoard, opts *[9][9][9]bool) *StrategyStep {
+	for _, c := range b.Constraints() {
+		thermo, ok := c.(board.ThermometerConstraint)
+		if !ok {
+			continue
+		}
+
+		n := len(thermo.Cells)
+		low := make([]int, n)
+		high := make([]int, n)
+		for i, cell := range thermo.Cells {
+			low[i], high[i] = valueRange(b, opts, cell.X, cell.Y)
+			if i > 0 && low[i-1]+1 > low[i] {
+				low[i] = low[i-1] + 1
+			}
+		}
+		for i := n - 2; i >= 0; i-- {
+			if high[i+1]-1 < high[i] {
+				high[i] = high[i+1] - 1
+			}
+		}
+
+		var actions []StrategyAction
+		for i, cell := range thermo.Cells {
+			actions = appendOutsideRange(actions, b, opts, cell.X, cell.Y, low[i], high[i])
+		}
+		if len(actions) > 0 {
+			return &StrategyStep{name: StrategyNameThermometerStrategy, actions: actions}
+		}
+	}
+	return nil
+}
+
+// ArrowStrategy limits the circle to the sums the arrow can make and the arrow to what still fits in the circle
+func ArrowStrategy(b *board.SudokuBoard, opts *[9][9][9]bool) *StrategyStep {
+	for _, c := range b.Constraints() {
+		arrow, ok := c.(board.ArrowConstraint)
+		if !ok {
+			continue
+		}
+
+		lows := make([]int, len(arrow.Path))
+		highs := make([]int, len(arrow.Path))
+		minSum, maxSum := 0, 0
+		for i, cell := range arrow.Path {
+			lows[i], highs[i] = valueRange(b, opts, cell.X, cell.Y)
+			minSum += lows[i]
+			maxSum += highs[i]
+		}
+		circleLow, circleHigh := valueRange(b, opts, arrow.Circle.X, arrow.Circle.Y)
+
+		actions := appendOutsideRange(nil, b, opts, arrow.Circle.X, arrow.Circle.Y, minSum, maxSum)
+		for i, cell := range arrow.Path {
+			low := circleLow - (maxSum - highs[i])
+			high := circleHigh - (minSum - lows[i])
+			actions = appendOutsideRange(actions, b, opts, cell.X, cell.Y, low, high)
+		}
+		if len(actions) > 0 {
+			return &StrategyStep{name: StrategyNameArrowStrategy, actions: actions}
+		}
+	}
+	return nil
+}
+
+// SandwichStrategy removes the 1s and 9s from cells where the other end of the sandwich can not be placed
+func SandwichStrategy(b *board.SudokuBoard, opts *[9][9][9]bool) *StrategyStep {
+	for _, c := range b.Constraints() {
+		sandwich, ok := c.(board.SandwichConstraint)
+		if !ok {
+			continue
+		}
+
+		cells := sandwich.Cells()
+		var actions []StrategyAction
+		for _, end := range []int{1, 9} {
+			other := 10 - end
+			for i, cell := range cells {
+				if b.GetAt(cell.X, cell.Y) != 0 || !opts[cell.X][cell.Y][end-1] {
+					continue
+				}
+				possible := false
+				for j, otherCell := range cells {
+					if j == i {
+						continue
+					}
+					if b.GetAt(otherCell.X, otherCell.Y) != other && !opts[otherCell.X][otherCell.Y][other-1] {
+						continue
+					}
+					if board.SandwichPossible(sandwich.Sum, abs(i-j)-1) {
+						possible = true
+						break
+					}
+				}
+				if !possible {
+					actions = append(actions, StrategyAction{set: true, opts: true, x: cell.X, y: cell.Y, value: end})
+				}
+			}
+		}
+		if len(actions) > 0 {
+			return &StrategyStep{name: StrategyNameSandwichStrategy, actions: actions}
+		}
+	}
+	return nil
+}
+
+// valueRange returns the smallest and largest value the cell can still hold
+func valueRange(b *board.SudokuBoard, opts *[9][9][9]bool, x, y int) (int, int) {
+	if value := b.GetAt(x, y); value != 0 {
+		return value, value
+	}
+	low, high := 10, 0
+	for v := 0; v < 9; v++ {
+		if opts[x][y][v] {
+			low = min(low, v+1)
+			high = max(high, v+1)
+		}
+	}
+	return low, high
+}
+
+// appendOutsideRange adds an action removing every option of an empty cell outside the range [low,high]
+func appendOutsideRange(actions []StrategyAction, b *board.SudokuBoard, opts *[9][9][9]bool, x, y, low, high int,
+) []StrategyAction {
+	if b.GetAt(x, y) != 0 {
+		return actions
+	}
+	for v := 1; v <= 9; v++ {
+		if opts[x][y][v-1] && (v < low || v > high) {
+			actions = append(actions, StrategyAction{set: true, opts: true, x: x, y: y, value: v})
+		}
+	}
+	return actions
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
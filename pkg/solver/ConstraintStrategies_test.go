@@ -0,0 +1,158 @@
+package solver
+
+import (
+	"reflect"
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/board"
+)
+
+func TestConstraintStrategies(t *testing.T) {
+	type args struct {
+		b *board.SudokuBoard
+		s StrategyMethod
+	}
+	tests := []struct {
+		name string
+		args args
+		want []*StrategyStep
+	}{
+		{
+			name: "Thermometer empty",
+			args: args{
+				b: (&board.SudokuBoard{}).AddConstraints(board.ThermometerConstraint{
+					Cells: []board.Cell{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}},
+				}),
+				s: ThermometerStrategy,
+			},
+			want: []*StrategyStep{
+				{
+					name: StrategyNameThermometerStrategy,
+					actions: []StrategyAction{
+						{set: true, opts: true, x: 0, y: 0, value: 8},
+						{set: true, opts: true, x: 0, y: 0, value: 9},
+						{set: true, opts: true, x: 1, y: 0, value: 1},
+						{set: true, opts: true, x: 1, y: 0, value: 9},
+						{set: true, opts: true, x: 2, y: 0, value: 1},
+						{set: true, opts: true, x: 2, y: 0, value: 2},
+					},
+				},
+				nil,
+			},
+		},
+		{
+			name: "Thermometer follows options",
+			args: args{
+				b: board.FromNumbers([9][9]int{
+					{0, 0, 0, 0, 0, 0, 0, 0, 0},
+					{0, 0, 0, 0, 0, 0, 0, 0, 0},
+					{0, 0, 0, 0, 0, 0, 0, 0, 0},
+					{0, 0, 0, 0, 0, 0, 0, 0, 0},
+					{0, 0, 0, 0, 0, 0, 0, 0, 0},
+					{8, 0, 0, 0, 0, 0, 0, 0, 0},
+					{9, 0, 0, 0, 0, 0, 0, 0, 0},
+					{0, 0, 0, 0, 0, 0, 0, 0, 0},
+					{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				}).AddConstraints(board.ThermometerConstraint{
+					Cells: []board.Cell{{X: 0, Y: 0}, {X: 0, Y: 1}},
+				}),
+				s: ThermometerStrategy,
+			},
+			want: []*StrategyStep{
+				{
+					name: StrategyNameThermometerStrategy,
+					actions: []StrategyAction{
+						{set: true, opts: true, x: 0, y: 0, value: 7},
+						{set: true, opts: true, x: 0, y: 1, value: 1},
+					},
+				},
+				nil,
+			},
+		},
+		{
+			name: "Arrow empty",
+			args: args{
+				b: (&board.SudokuBoard{}).AddConstraints(board.ArrowConstraint{
+					Circle: board.Cell{X: 0, Y: 0},
+					Path:   []board.Cell{{X: 1, Y: 1}, {X: 2, Y: 2}},
+				}),
+				s: ArrowStrategy,
+			},
+			want: []*StrategyStep{
+				{
+					name: StrategyNameArrowStrategy,
+					actions: []StrategyAction{
+						{set: true, opts: true, x: 0, y: 0, value: 1},
+						{set: true, opts: true, x: 1, y: 1, value: 9},
+						{set: true, opts: true, x: 2, y: 2, value: 9},
+					},
+				},
+				nil,
+			},
+		},
+		{
+			name: "Sandwich full row",
+			args: args{
+				b: (&board.SudokuBoard{}).AddConstraints(board.SandwichConstraint{Index: 0, Sum: 35}),
+				s: SandwichStrategy,
+			},
+			want: []*StrategyStep{
+				{
+					name: StrategyNameSandwichStrategy,
+					actions: []StrategyAction{
+						{set: true, opts: true, x: 1, y: 0, value: 1},
+						{set: true, opts: true, x: 2, y: 0, value: 1},
+						{set: true, opts: true, x: 3, y: 0, value: 1},
+						{set: true, opts: true, x: 4, y: 0, value: 1},
+						{set: true, opts: true, x: 5, y: 0, value: 1},
+						{set: true, opts: true, x: 6, y: 0, value: 1},
+						{set: true, opts: true, x: 7, y: 0, value: 1},
+						{set: true, opts: true, x: 1, y: 0, value: 9},
+						{set: true, opts: true, x: 2, y: 0, value: 9},
+						{set: true, opts: true, x: 3, y: 0, value: 9},
+						{set: true, opts: true, x: 4, y: 0, value: 9},
+						{set: true, opts: true, x: 5, y: 0, value: 9},
+						{set: true, opts: true, x: 6, y: 0, value: 9},
+						{set: true, opts: true, x: 7, y: 0, value: 9},
+					},
+				},
+				nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := GetPossibleValues(tt.args.b)
+			for _, step := range tt.want {
+				if got := tt.args.s(tt.args.b, &opts); !reflect.DeepEqual(got, step) {
+					t.Errorf("got %v, want %v", got, step)
+				}
+				if step != nil {
+					ApplyStep(tt.args.b, *step, &opts)
+				}
+			}
+		})
+	}
+}
+
+func TestSolveByGuessing_LineConstraints(t *testing.T) {
+	b := board.FromNumbers([9][9]int{
+		{0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{7, 1, 5, 9, 3, 4, 8, 2, 6},
+		{6, 8, 3, 2, 4, 9, 1, 5, 7},
+		{2, 4, 9, 1, 5, 7, 6, 8, 3},
+		{1, 5, 7, 6, 8, 3, 2, 4, 9},
+	}).AddConstraints(
+		board.ThermometerConstraint{Cells: []board.Cell{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}}},
+		board.ArrowConstraint{Circle: board.Cell{X: 0, Y: 4}, Path: []board.Cell{{X: 1, Y: 4}, {X: 2, Y: 4}}},
+		board.SandwichConstraint{Index: 3, Sum: 30},
+	)
+	SolveByGuessing(DefaultGuessConfig(), b)
+	if !board.IsSolved(b) {
+		t.Errorf("SolveByGuessing() did not solve the board\n%v", b)
+	}
+}
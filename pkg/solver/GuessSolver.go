@@ -2,6 +2,7 @@ package solver
 
 import (
 	"fmt"
+	"math/rand"
 
 	"droidkfx.com/sudoku/pkg/board"
 )
@@ -23,6 +24,22 @@ func NewStaticOrderGuesser(order []int) GuessOrderProvider {
 	}
 }
 
+// NewRandomOrderGuesser tries the numbers of every cell in its own random order
+func NewRandomOrderGuesser() GuessOrderProvider {
+	return NewSeededOrderGuesser(rand.New(rand.NewSource(rand.Int63())))
+}
+
+// NewSeededOrderGuesser works like NewRandomOrderGuesser with the orders taken from rng, so they can be repeated
+func NewSeededOrderGuesser(rng *rand.Rand) GuessOrderProvider {
+	orders := [81][]int{}
+	for i := range orders {
+		orders[i] = rng.Perm(9)
+	}
+	return func(i, j, v int) int {
+		return orders[i*9+j][v]
+	}
+}
+
 func DefaultGuessConfig() GuessSolverConfig {
 	return GuessSolverConfig{
 		NumberOrder: NewStaticOrderGuesser([]int{0, 1, 2, 3, 4, 5, 6, 7, 8}),
@@ -46,6 +63,14 @@ func SolveByGuessing(cfg GuessSolverConfig, board *board.SudokuBoard) SolveMetri
 	return metrics
 }
 
+// SolveByGuessingFromCandidates works like SolveByGuessing but only guesses numbers allowed by the candidates
+func SolveByGuessingFromCandidates(cfg GuessSolverConfig, b *board.SudokuBoard, candidates board.CandidateGrid,
+) SolveMetrics {
+	metrics := SolveMetrics{}
+	solveByGuessing(&cfg, b, GetCandidateValues(b, candidates), 0, 0, &metrics)
+	return metrics
+}
+
 func solveByGuessing(cfg *GuessSolverConfig, board *board.SudokuBoard, values [9][9][9]bool, x, y int,
 	metrics *SolveMetrics) bool {
 	if board.GetAt(x, y) == 0 {
@@ -77,16 +102,19 @@ func solveByGuessing(cfg *GuessSolverConfig, board *board.SudokuBoard, values [9
 	return board.GetAt(x, y) != 0
 }
 
-func tryValue(cfg *GuessSolverConfig, board *board.SudokuBoard, values [9][9][9]bool, x, y, value int,
+func tryValue(cfg *GuessSolverConfig, b *board.SudokuBoard, values [9][9][9]bool, x, y, value int,
 	metrics *SolveMetrics) bool {
-	board.SetAt(x, y, value)
+	b.SetAt(x, y, value)
+	if !board.VerifyConstraints(b) {
+		return false
+	}
 	nextX, nextY, hasNext := getNextCoords(x, y)
 	if !hasNext {
 		return true
 	}
 
-	propagateNumberSetToOptions(&values, x, y, value)
-	return solveByGuessing(cfg, board, values, nextX, nextY, metrics)
+	propagateNumberSetToOptions(b, &values, x, y, value)
+	return solveByGuessing(cfg, b, values, nextX, nextY, metrics)
 }
 
 func getNextCoords(x, y int) (int, int, bool) {
@@ -1,6 +1,7 @@
 package solver
 
 import (
+	"math/rand"
 	"runtime"
 	"testing"
 
@@ -121,3 +122,21 @@ func TestSolveByGuessing(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSeededOrderGuesser(t *testing.T) {
+	a := NewSeededOrderGuesser(rand.New(rand.NewSource(7)))
+	b := NewSeededOrderGuesser(rand.New(rand.NewSource(7)))
+	for cell := 0; cell < 81; cell++ {
+		seen := [9]bool{}
+		for v := 0; v < 9; v++ {
+			number := a(cell/9, cell%9, v)
+			if number != b(cell/9, cell%9, v) {
+				t.Fatalf("guessers with the same seed differ at cell %d", cell)
+			}
+			seen[number] = true
+		}
+		if seen != [9]bool{true, true, true, true, true, true, true, true, true} {
+			t.Errorf("cell %d does not try every number", cell)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package solver
 
-import "droidkfx.com/sudoku/pkg/board"
+import (
+	"fmt"
+
+	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/game"
+)
 
 type StrategyAction struct {
 	set   bool
@@ -13,11 +18,17 @@ type StrategyAction struct {
 type StrategyName string
 
 const (
-	StrategyNamePsychicStrategy       StrategyName = "Psychic"
-	StrategyNameLastCandidateStrategy StrategyName = "LastCandidate"
-	StrategyNameLastInRowStrategy     StrategyName = "LastInRow"
-	StrategyNameLastInColumnStrategy  StrategyName = "LastInColumn"
-	StrategyNameLastInRegionStrategy  StrategyName = "LastInRegion"
+	StrategyNamePsychicStrategy          StrategyName = "Psychic"
+	StrategyNameLastCandidateStrategy    StrategyName = "LastCandidate"
+	StrategyNameLastInRowStrategy        StrategyName = "LastInRow"
+	StrategyNameLastInColumnStrategy     StrategyName = "LastInColumn"
+	StrategyNameLastInRegionStrategy     StrategyName = "LastInRegion"
+	StrategyNameThermometerStrategy      StrategyName = "Thermometer"
+	StrategyNameArrowStrategy            StrategyName = "Arrow"
+	StrategyNameSandwichStrategy         StrategyName = "Sandwich"
+	StrategyNameLockedCandidatesStrategy StrategyName = "LockedCandidates"
+	StrategyNameNakedPairStrategy        StrategyName = "NakedPair"
+	StrategyNameXWingStrategy            StrategyName = "XWing"
 )
 
 type StrategyDifficulty uint8
@@ -30,6 +41,100 @@ const (
 	StrategyDifficultyImpossible
 )
 
+var strategyDifficultyNames = map[StrategyDifficulty]string{
+	StrategyDifficultyEasy:       "easy",
+	StrategyDifficultyMedium:     "medium",
+	StrategyDifficultyHard:       "hard",
+	StrategyDifficultyVeryHard:   "veryHard",
+	StrategyDifficultyImpossible: "impossible",
+}
+
+func (d StrategyDifficulty) String() string {
+	if name, found := strategyDifficultyNames[d]; found {
+		return name
+	}
+	return fmt.Sprintf("StrategyDifficulty(%d)", uint8(d))
+}
+
+func (d StrategyDifficulty) MarshalText() ([]byte, error) {
+	name, found := strategyDifficultyNames[d]
+	if !found {
+		return nil, fmt.Errorf("unknown difficulty %d", uint8(d))
+	}
+	return []byte(name), nil
+}
+
+func (d *StrategyDifficulty) UnmarshalText(text []byte) error {
+	parsed, err := ParseStrategyDifficulty(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// ParseStrategyDifficulty reads the name of a difficulty as returned by String
+func ParseStrategyDifficulty(name string) (StrategyDifficulty, error) {
+	for d, n := range strategyDifficultyNames {
+		if n == name {
+			return d, nil
+		}
+	}
+	return StrategyDifficultyEasy, fmt.Errorf("unknown difficulty %q", name)
+}
+
+// Difficulty returns how hard the strategy is for a player, unknown strategies count as impossible
+func (n StrategyName) Difficulty() StrategyDifficulty {
+	if d, found := strategyDifficultyMap[n]; found {
+		return d
+	}
+	return StrategyDifficultyImpossible
+}
+
+// ParseStrategyName checks the name is one of the strategies of the solver
+func ParseStrategyName(name string) (StrategyName, error) {
+	if _, found := strategyDifficultyMap[StrategyName(name)]; !found {
+		return "", fmt.Errorf("unknown strategy %q", name)
+	}
+	return StrategyName(name), nil
+}
+
+/*
+Rating describes what it takes to solve a puzzle with the strategy solver. Difficulty is that of the hardest strategy
+needed and Strategies lists each strategy used once, in the order they were first needed.
+*/
+type Rating struct {
+	Difficulty StrategyDifficulty `json:"difficulty"`
+	Strategies []StrategyName     `json:"strategies"`
+}
+
+// Rate solves a copy of the board with SolveByStrategies and rates the steps it took
+func Rate(b *board.SudokuBoard) Rating {
+	return RateSteps(SolveByStrategies(b.Copy()))
+}
+
+func RateSteps(steps []StrategyStep) Rating {
+	rating := Rating{}
+	for _, step := range steps {
+		if !rating.Uses(step.name) {
+			rating.Strategies = append(rating.Strategies, step.name)
+		}
+		if d := step.name.Difficulty(); d > rating.Difficulty {
+			rating.Difficulty = d
+		}
+	}
+	return rating
+}
+
+func (r Rating) Uses(name StrategyName) bool {
+	for _, n := range r.Strategies {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
 type StrategyStep struct {
 	actions []StrategyAction
 	name    StrategyName
@@ -38,61 +143,121 @@ type StrategyStep struct {
 type StrategyMethod func(b *board.SudokuBoard, opts *[9][9][9]bool) *StrategyStep
 
 var strategyDifficultyMap = map[StrategyName]StrategyDifficulty{
-	StrategyNameLastInColumnStrategy:  StrategyDifficultyEasy,
-	StrategyNameLastInRegionStrategy:  StrategyDifficultyEasy,
-	StrategyNameLastInRowStrategy:     StrategyDifficultyEasy,
-	StrategyNameLastCandidateStrategy: StrategyDifficultyMedium,
-	StrategyNamePsychicStrategy:       StrategyDifficultyImpossible,
+	StrategyNameLastInColumnStrategy:     StrategyDifficultyEasy,
+	StrategyNameLastInRegionStrategy:     StrategyDifficultyEasy,
+	StrategyNameLastInRowStrategy:        StrategyDifficultyEasy,
+	StrategyNameLastCandidateStrategy:    StrategyDifficultyMedium,
+	StrategyNameThermometerStrategy:      StrategyDifficultyMedium,
+	StrategyNameArrowStrategy:            StrategyDifficultyMedium,
+	StrategyNameSandwichStrategy:         StrategyDifficultyMedium,
+	StrategyNameLockedCandidatesStrategy: StrategyDifficultyMedium,
+	StrategyNameNakedPairStrategy:        StrategyDifficultyMedium,
+	StrategyNameXWingStrategy:            StrategyDifficultyHard,
+	StrategyNamePsychicStrategy:          StrategyDifficultyImpossible,
 }
 
-// List of strategies, this list should be sorted by difficulty since they will be tried in order
+/*
+List of strategies, this list should be sorted by difficulty since they will be tried in order. Guessing with
+PsychicStrategy comes after all of them, see solveNextStep.
+*/
 var strategies = []StrategyMethod{
 	LastInRowStrategy,
 	LastInColumnStrategy,
 	LastInRegionStrategy,
 	LastCandidateStrategy,
-	PsychicStrategy,
+	ThermometerStrategy,
+	ArrowStrategy,
+	SandwichStrategy,
+	LockedCandidatesStrategy,
+	NakedPairStrategy,
+	XWingStrategy,
 }
 
 func SolveByStrategies(b *board.SudokuBoard) []StrategyStep {
-	opts := GetPossibleValues(b)
+	return SolveByStrategiesFromCandidates(b, board.FullCandidateGrid())
+}
+
+/*
+SolveByStrategiesFromCandidates works like SolveByStrategies but starts from the given candidates rather than every
+number the board allows, this is how Sukaku puzzles are solved. Candidates the numbers on the board already rule out
+are dropped. If no strategy applies, as on a board with no solution, the steps found so far are returned and the board
+is left unsolved.
+*/
+func SolveByStrategiesFromCandidates(b *board.SudokuBoard, candidates board.CandidateGrid) []StrategyStep {
+	opts := GetCandidateValues(b, candidates)
 	var steps []StrategyStep
+	var psychic psychicSolution
 	for !board.IsSolved(b) {
-		step := *SolveNextStep(b, &opts)
-		steps = append(steps, step)
-		ApplyStep(b, step, &opts)
+		step := solveNextStep(b, &opts, psychic.strategy)
+		if step == nil {
+			break
+		}
+		steps = append(steps, *step)
+		ApplyStep(b, *step, &opts)
 	}
 
 	return steps
 }
 
 func ApplyStep(b *board.SudokuBoard, step StrategyStep, opts *[9][9][9]bool) {
-	for _, action := range step.actions {
-		if action.set {
-			if action.opts {
-				opts[action.x][action.y][action.value-1] = false
-			} else {
-				b.SetAt(action.x, action.y, action.value)
-				propagateNumberSetToOptions(opts, action.x, action.y, action.value)
-			}
-		} else {
-			if action.opts {
-				opts[action.x][action.y][action.value-1] = true
-			} else {
-				b.SetAt(action.x, action.y, 0)
-			}
+	for _, move := range step.Moves() {
+		switch move.Kind {
+		case game.MovePlace:
+			b.SetAt(move.X, move.Y, move.Value)
+			propagateNumberSetToOptions(b, opts, move.X, move.Y, move.Value)
+		case game.MoveErase:
+			b.SetAt(move.X, move.Y, 0)
+		case game.MoveAddMark:
+			opts[move.X][move.Y][move.Value-1] = true
+		case game.MoveRemoveMark:
+			opts[move.X][move.Y][move.Value-1] = false
 		}
 	}
 }
 
+/*
+Move converts the action to the move a player would make, placing or erasing a number or adding or removing a pencil
+mark for an option. The options of the solver are the pencil marks of a player.
+*/
+func (a StrategyAction) Move() game.Move {
+	kind := game.MovePlace
+	switch {
+	case a.set && a.opts:
+		kind = game.MoveRemoveMark
+	case !a.set && a.opts:
+		kind = game.MoveAddMark
+	case !a.set && !a.opts:
+		kind = game.MoveErase
+	}
+	return game.Move{Kind: kind, X: a.x, Y: a.y, Value: a.value}
+}
+
+// Moves converts every action of the step, they can be applied to a game.Game to show a hint
+func (s StrategyStep) Moves() []game.Move {
+	moves := make([]game.Move, len(s.actions))
+	for i, action := range s.actions {
+		moves[i] = action.Move()
+	}
+	return moves
+}
+
+func (s StrategyStep) Name() StrategyName {
+	return s.name
+}
+
 func SolveNextStep(b *board.SudokuBoard, opts *[9][9][9]bool) *StrategyStep {
+	return solveNextStep(b, opts, PsychicStrategy)
+}
+
+// solveNextStep tries the strategies in order and guesses if none of them applies
+func solveNextStep(b *board.SudokuBoard, opts *[9][9][9]bool, guess StrategyMethod) *StrategyStep {
 	for _, strategy := range strategies {
 		step := strategy(b, opts)
 		if step != nil {
 			return step
 		}
 	}
-	return nil
+	return guess(b, opts)
 }
 
 func findNextEmpty(x, y int, b *board.SudokuBoard) (int, int, bool) {
@@ -115,22 +280,46 @@ func findNextEmpty(x, y int, b *board.SudokuBoard) (int, int, bool) {
 	return 0, 0, false
 }
 
-var tmpPsychicBoard *board.SudokuBoard = nil
+/*
+PsychicStrategy solves a copy of the board by guessing and places the first number of it, the copy is not kept so
+boards can be rated concurrently. A solve that guesses more than once keeps it instead, see psychicSolution.
+*/
+func PsychicStrategy(b *board.SudokuBoard, opts *[9][9][9]bool) *StrategyStep {
+	return psychicStep(b, guessSolution(b, opts))
+}
+
+/*
+psychicSolution is the solution PsychicStrategy guessed in one solve, kept so later guesses place its numbers rather
+than solving again. The numbers the other strategies place are in every solution, so they do not change it.
+*/
+type psychicSolution struct {
+	solution *board.SudokuBoard
+}
 
-func PsychicStrategy(b *board.SudokuBoard, _ *[9][9][9]bool) *StrategyStep {
-	if tmpPsychicBoard == nil || tmpPsychicBoard != b {
-		tmpPsychicBoard = b.Copy()
-		SolveByGuessing(DefaultGuessConfig(), tmpPsychicBoard)
+func (p *psychicSolution) strategy(b *board.SudokuBoard, opts *[9][9][9]bool) *StrategyStep {
+	if p.solution == nil {
+		p.solution = guessSolution(b, opts)
 	}
+	return psychicStep(b, p.solution)
+}
+
+func guessSolution(b *board.SudokuBoard, opts *[9][9][9]bool) *board.SudokuBoard {
+	solution := b.Copy()
+	SolveByGuessingFromCandidates(DefaultGuessConfig(), solution, *opts)
+	return solution
+}
+
+// psychicStep places the number of the solution in the first empty cell, nil if guessing found no solution
+func psychicStep(b, solution *board.SudokuBoard) *StrategyStep {
 	x, y, hasNext := findNextEmpty(0, 0, b)
 
 	for hasNext {
-		solvedValue := tmpPsychicBoard.GetAt(x, y)
+		solvedValue := solution.GetAt(x, y)
 		if b.GetAt(x, y) == 0 && solvedValue != 0 {
 			return &StrategyStep{
 				name: StrategyNamePsychicStrategy,
 				actions: []StrategyAction{
-					{set: true, opts: false, x: x, y: y, value: tmpPsychicBoard.GetAt(x, y)},
+					{set: true, opts: false, x: x, y: y, value: solvedValue},
 				},
 			}
 		}
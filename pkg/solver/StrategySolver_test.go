@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"droidkfx.com/sudoku/pkg/board"
+	"droidkfx.com/sudoku/pkg/game"
 )
 
 // func TestSolverTest(T *testing.T) {
@@ -404,3 +405,68 @@ func TestStrategies(t *testing.T) {
 		})
 	}
 }
+
+func TestStrategyStep_Moves(t *testing.T) {
+	puzzle := board.FromNumbers([9][9]int{
+		{0, 1, 5, 4, 2, 6, 7, 8, 9},
+		{4, 2, 6, 7, 8, 9, 3, 1, 5},
+		{7, 8, 9, 0, 1, 5, 4, 2, 6},
+		{1, 3, 4, 5, 6, 2, 8, 9, 7},
+		{5, 6, 2, 8, 9, 7, 1, 0, 4},
+		{8, 9, 7, 1, 3, 4, 5, 6, 2},
+		{2, 5, 3, 6, 4, 1, 9, 7, 8},
+		{6, 4, 1, 9, 7, 0, 2, 5, 3},
+		{9, 7, 8, 2, 5, 3, 6, 4, 0},
+	})
+	solved := puzzle.Copy()
+	steps := SolveByStrategies(solved)
+
+	g := game.New(puzzle)
+	for _, step := range steps {
+		if err := g.Apply(step.Moves()...); err != nil {
+			t.Fatalf("Apply(%v) error = %v", step.Moves(), err)
+		}
+	}
+	if got := g.Board().Board(); !reflect.DeepEqual(got, solved) {
+		t.Errorf("replaying the steps = \n%v, want \n%v", got, solved)
+	}
+
+	actions := map[StrategyAction]game.MoveKind{
+		{set: true, opts: false, value: 1}:  game.MovePlace,
+		{set: false, opts: false, value: 1}: game.MoveErase,
+		{set: true, opts: true, value: 1}:   game.MoveRemoveMark,
+		{set: false, opts: true, value: 1}:  game.MoveAddMark,
+	}
+	for action, want := range actions {
+		if got := action.Move().Kind; got != want {
+			t.Errorf("%+v.Move().Kind = %v, want %v", action, got, want)
+		}
+	}
+}
+
+func Test_psychicSolution(t *testing.T) {
+	b := &board.SudokuBoard{}
+	opts := GetCandidateValues(b, board.FullCandidateGrid())
+	var psychic psychicSolution
+	for i := 0; i < 3; i++ {
+		step := psychic.strategy(b, &opts)
+		if step == nil {
+			t.Fatalf("strategy() = nil on step %d", i)
+		}
+		ApplyStep(b, *step, &opts)
+	}
+	guessed := psychic.solution
+	for i := 0; i < 3; i++ {
+		ApplyStep(b, *psychic.strategy(b, &opts), &opts)
+	}
+	if psychic.solution != guessed {
+		t.Errorf("strategy() guessed the solution again")
+	}
+	for x := 0; x < 9; x++ {
+		for y := 0; y < 9; y++ {
+			if value := b.GetAt(x, y); value != 0 && value != guessed.GetAt(x, y) {
+				t.Errorf("strategy() placed %d at %d,%d, the guessed solution has %d", value, x, y, guessed.GetAt(x, y))
+			}
+		}
+	}
+}
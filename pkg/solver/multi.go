@@ -0,0 +1,147 @@
+package solver
+
+import "droidkfx.com/sudoku/pkg/board"
+
+// multiCell is a cell on the whole area of a multi board together with the grids covering it
+type multiCell struct {
+	x, y  int
+	grids []int
+}
+
+/*
+GetMultiPossibleValues works out the options of every grid of the board with GetPossibleValues. Options of a shared
+cell are then narrowed to the numbers every grid covering it allows, so a number placed in one grid rules out options
+in the grids it overlaps.
+*/
+func GetMultiPossibleValues(m *board.MultiBoard) [][9][9][9]bool {
+	opts := make([][9][9][9]bool, m.GridCount())
+	for i := range opts {
+		opts[i] = GetPossibleValues(m.Grid(i))
+	}
+	for _, cell := range coveredCells(m) {
+		if len(cell.grids) < 2 {
+			continue
+		}
+		shared := cellOptions(m, opts, cell)
+		for _, i := range cell.grids {
+			offset := m.Offset(i)
+			opts[i][cell.x-offset.X][cell.y-offset.Y] = shared
+		}
+	}
+	return opts
+}
+
+// CountMultiSolutions counts the solutions of the board up to limit, the board itself is not modified
+func CountMultiSolutions(m *board.MultiBoard, limit int) int {
+	if !board.VerifyMultiBoard(m) {
+		return 0
+	}
+
+	cfg := DefaultGuessConfig()
+	work := m.Copy()
+	count := 0
+	searchMultiSolutions(&cfg, work, coveredCells(work), GetMultiPossibleValues(work), func() bool {
+		count++
+		return count < limit
+	})
+	return count
+}
+
+/*
+SolveMultiBoard fills in every grid of the board, guessing in the cell with the fewest options left across all grids.
+A number placed in a shared cell is placed in every grid covering it and removes options from all of them. It returns
+false and leaves the board untouched if there is no solution.
+*/
+func SolveMultiBoard(cfg GuessSolverConfig, m *board.MultiBoard) bool {
+	if !board.VerifyMultiBoard(m) {
+		return false
+	}
+
+	solved := false
+	searchMultiSolutions(&cfg, m, coveredCells(m), GetMultiPossibleValues(m), func() bool {
+		solved = true
+		return false
+	})
+	return solved
+}
+
+// searchMultiSolutions works like searchSolutions on every grid of the board at once
+func searchMultiSolutions(cfg *GuessSolverConfig, m *board.MultiBoard, cells []multiCell, opts [][9][9][9]bool,
+	found func() bool) bool {
+	best, bestCount := -1, 10
+	var bestOptions [9]bool
+	for i, cell := range cells {
+		if m.GetAt(cell.x, cell.y) != 0 {
+			continue
+		}
+		options := cellOptions(m, opts, cell)
+		count := 0
+		for _, possible := range options {
+			if possible {
+				count++
+			}
+		}
+		if count < bestCount {
+			best, bestCount, bestOptions = i, count, options
+		}
+	}
+	if best == -1 {
+		return found()
+	}
+
+	cell := cells[best]
+	first := m.Offset(cell.grids[0])
+	for v := 0; v < 9; v++ {
+		number := cfg.NumberOrder(cell.x-first.X, cell.y-first.Y, v)
+		if !bestOptions[number] {
+			continue
+		}
+		m.SetAt(cell.x, cell.y, number+1)
+		if verifyMultiConstraints(m, cell) {
+			next := make([][9][9][9]bool, len(opts))
+			copy(next, opts)
+			for _, i := range cell.grids {
+				offset := m.Offset(i)
+				propagateNumberSetToOptions(m.Grid(i), &next[i], cell.x-offset.X, cell.y-offset.Y, number+1)
+			}
+			if !searchMultiSolutions(cfg, m, cells, next, found) {
+				return false
+			}
+		}
+	}
+	m.SetAt(cell.x, cell.y, 0)
+	return true
+}
+
+func verifyMultiConstraints(m *board.MultiBoard, cell multiCell) bool {
+	for _, i := range cell.grids {
+		if !board.VerifyConstraints(m.Grid(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// cellOptions returns the numbers every grid covering the cell still allows
+func cellOptions(m *board.MultiBoard, opts [][9][9][9]bool, cell multiCell) [9]bool {
+	result := [9]bool{true, true, true, true, true, true, true, true, true}
+	for _, i := range cell.grids {
+		offset := m.Offset(i)
+		for v := 0; v < 9; v++ {
+			result[v] = result[v] && opts[i][cell.x-offset.X][cell.y-offset.Y][v]
+		}
+	}
+	return result
+}
+
+func coveredCells(m *board.MultiBoard) []multiCell {
+	var cells []multiCell
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			if grids := m.GridsAt(x, y); len(grids) > 0 {
+				cells = append(cells, multiCell{x: x, y: y, grids: grids})
+			}
+		}
+	}
+	return cells
+}
@@ -0,0 +1,65 @@
+package solver
+
+import (
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/board"
+)
+
+func TestSolveMultiBoard(t *testing.T) {
+	m := board.NewSamuraiBoard()
+	if !SolveMultiBoard(GuessConfig(NewRandomOrderGuesser()), m) {
+		t.Fatalf("SolveMultiBoard() = false on an empty board")
+	}
+	if !board.IsMultiBoardSolved(m) {
+		t.Fatalf("SolveMultiBoard() left an unsolved board:\n%v", m)
+	}
+
+	// every shared box can be cleared since the rest of the outer grids still pins it down
+	puzzle := m.Copy()
+	for _, box := range []board.Cell{{X: 6, Y: 6}, {X: 12, Y: 6}, {X: 6, Y: 12}, {X: 12, Y: 12}} {
+		for i := 0; i < 9; i++ {
+			puzzle.SetAt(box.X+i%3, box.Y+i/3, 0)
+		}
+	}
+	puzzle.SetAt(0, 0, 0)
+	puzzle.SetAt(20, 20, 0)
+	if got := CountMultiSolutions(puzzle, 2); got != 1 {
+		t.Errorf("CountMultiSolutions() = %d, want 1", got)
+	}
+	if !SolveMultiBoard(DefaultGuessConfig(), puzzle) {
+		t.Fatalf("SolveMultiBoard() = false on a puzzle")
+	}
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			if puzzle.GetAt(x, y) != m.GetAt(x, y) {
+				t.Fatalf("SolveMultiBoard() = \n%v, want \n%v", puzzle, m)
+			}
+		}
+	}
+}
+
+func TestGetMultiPossibleValues(t *testing.T) {
+	m := board.NewSamuraiBoard()
+	// a 5 in the top left grid rules out 5 for the shared box of the centre grid
+	m.SetAt(0, 6, 5)
+	opts := GetMultiPossibleValues(m)
+	if opts[2][0][0][4] {
+		t.Errorf("centre grid (0, 0) still allows 5")
+	}
+	if opts[0][6][6][4] {
+		t.Errorf("top left grid (6, 6) still allows 5")
+	}
+	if !opts[2][3][0][4] {
+		t.Errorf("centre grid (3, 0) does not allow 5")
+	}
+}
+
+func TestCountMultiSolutions_Invalid(t *testing.T) {
+	m := board.NewSamuraiBoard()
+	m.SetAt(6, 6, 4)
+	m.SetAt(6, 12, 4)
+	if got := CountMultiSolutions(m, 2); got != 0 {
+		t.Errorf("CountMultiSolutions() = %d, want 0", got)
+	}
+}
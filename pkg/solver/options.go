@@ -2,7 +2,7 @@ package solver
 
 import "droidkfx.com/sudoku/pkg/board"
 
-func propagateNumberSetToOptions(opts *[9][9][9]bool, x, y, value int) {
+func propagateNumberSetToOptions(b *board.SudokuBoard, opts *[9][9][9]bool, x, y, value int) {
 	for i := 0; i < 9; i++ {
 		opts[x][i][value-1] = false
 		opts[i][y][value-1] = false
@@ -16,6 +16,16 @@ func propagateNumberSetToOptions(opts *[9][9][9]bool, x, y, value int) {
 			opts[regionX*3+i][regionY*3+j][value-1] = false
 		}
 	}
+	propagateConstraintsToOptions(b, opts, x, y, value)
+}
+
+// propagateConstraintsToOptions removes the options excluded by the constraints of the board
+func propagateConstraintsToOptions(b *board.SudokuBoard, opts *[9][9][9]bool, x, y, value int) {
+	for _, c := range b.Constraints() {
+		for _, excluded := range c.Excludes(x, y, value) {
+			opts[excluded.X][excluded.Y][excluded.Value-1] = false
+		}
+	}
 }
 
 func GetPossibleValues(board *board.SudokuBoard) [9][9][9]bool {
@@ -33,6 +43,32 @@ func GetPossibleValues(board *board.SudokuBoard) [9][9][9]bool {
 			}
 		}
 	}
+
+	if len(board.Constraints()) > 0 {
+		for x := 0; x < 9; x++ {
+			for y := 0; y < 9; y++ {
+				if value := board.GetAt(x, y); value != 0 {
+					propagateConstraintsToOptions(board, &possibleValues, x, y, value)
+				}
+			}
+		}
+	}
+	return possibleValues
+}
+
+/*
+GetCandidateValues narrows the candidates down to the numbers GetPossibleValues allows, so filled cells are left
+without options and the numbers on the board still rule out their row, column and region.
+*/
+func GetCandidateValues(b *board.SudokuBoard, candidates board.CandidateGrid) [9][9][9]bool {
+	possibleValues := GetPossibleValues(b)
+	for x := 0; x < 9; x++ {
+		for y := 0; y < 9; y++ {
+			for v := 0; v < 9; v++ {
+				possibleValues[x][y][v] = possibleValues[x][y][v] && candidates[x][y][v]
+			}
+		}
+	}
 	return possibleValues
 }
 
@@ -0,0 +1,113 @@
+package solver
+
+import "droidkfx.com/sudoku/pkg/board"
+
+// CountSolutions counts the solutions of the board, it stops counting once limit solutions have been found. The board
+// itself is not modified.
+func CountSolutions(b *board.SudokuBoard, limit int) int {
+	return CountSolutionsFromCandidates(b, board.FullCandidateGrid(), limit)
+}
+
+// CountSolutionsFromCandidates counts the solutions that only use the given candidates, see CountSolutions
+func CountSolutionsFromCandidates(b *board.SudokuBoard, candidates board.CandidateGrid, limit int) int {
+	if !board.VerifyBoard(b) {
+		return 0
+	}
+
+	cfg := DefaultGuessConfig()
+	work := b.Copy()
+	count := 0
+	searchSolutions(&cfg, work, GetCandidateValues(work, candidates), func() bool {
+		count++
+		return count < limit
+	})
+	return count
+}
+
+// IsUnique returns true if the board has exactly one solution
+func IsUnique(b *board.SudokuBoard) bool {
+	return CountSolutions(b, 2) == 1
+}
+
+// FindSolutions returns up to limit solutions of the board, the board itself is not modified
+func FindSolutions(b *board.SudokuBoard, limit int) []*board.SudokuBoard {
+	if !board.VerifyBoard(b) || limit <= 0 {
+		return nil
+	}
+
+	cfg := DefaultGuessConfig()
+	work := b.Copy()
+	var solutions []*board.SudokuBoard
+	searchSolutions(&cfg, work, GetPossibleValues(work), func() bool {
+		solutions = append(solutions, work.Copy())
+		return len(solutions) < limit
+	})
+	return solutions
+}
+
+/*
+SolveByFewestOptions fills in the board by always guessing in the cell with the fewest options left, trying the
+numbers in the order given by the config. Unlike SolveByGuessing the order of the cells adapts to the board which
+keeps boards with many constraints fast to solve. It returns false and leaves the board untouched if there is no
+solution.
+*/
+func SolveByFewestOptions(cfg GuessSolverConfig, b *board.SudokuBoard) bool {
+	if !board.VerifyBoard(b) {
+		return false
+	}
+
+	solved := false
+	searchSolutions(&cfg, b, GetPossibleValues(b), func() bool {
+		solved = true
+		return false
+	})
+	return solved
+}
+
+// searchSolutions calls found for every solution of the board until it returns false, at which point the search stops
+// and the solution is left on the board. It returns false if the search was stopped.
+func searchSolutions(cfg *GuessSolverConfig, b *board.SudokuBoard, opts [9][9][9]bool, found func() bool) bool {
+	x, y, hasEmpty := findFewestOptions(b, &opts)
+	if !hasEmpty {
+		return found()
+	}
+
+	for v := 0; v < 9; v++ {
+		number := cfg.NumberOrder(x, y, v)
+		if !opts[x][y][number] {
+			continue
+		}
+		b.SetAt(x, y, number+1)
+		if board.VerifyConstraints(b) {
+			next := opts
+			propagateNumberSetToOptions(b, &next, x, y, number+1)
+			if !searchSolutions(cfg, b, next, found) {
+				return false
+			}
+		}
+	}
+	b.SetAt(x, y, 0)
+	return true
+}
+
+// findFewestOptions finds the empty cell with the fewest options left. When every cell is filled found is false.
+func findFewestOptions(b *board.SudokuBoard, opts *[9][9][9]bool) (int, int, bool) {
+	bestX, bestY, bestCount := 0, 0, 10
+	for x := 0; x < 9; x++ {
+		for y := 0; y < 9; y++ {
+			if b.GetAt(x, y) != 0 {
+				continue
+			}
+			count := 0
+			for v := 0; v < 9; v++ {
+				if opts[x][y][v] {
+					count++
+				}
+			}
+			if count < bestCount {
+				bestX, bestY, bestCount = x, y, count
+			}
+		}
+	}
+	return bestX, bestY, bestCount != 10
+}
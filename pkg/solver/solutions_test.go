@@ -0,0 +1,241 @@
+package solver
+
+import (
+	"reflect"
+	"testing"
+
+	"droidkfx.com/sudoku/pkg/board"
+)
+
+func TestCountSolutions(t *testing.T) {
+	type args struct {
+		board *board.SudokuBoard
+		limit int
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{
+			name: "solved board",
+			args: args{
+				limit: 2,
+				board: board.FromNumbers([9][9]int{
+					{3, 9, 8, 4, 6, 2, 5, 7, 1},
+					{4, 6, 2, 5, 7, 1, 3, 9, 8},
+					{5, 7, 1, 3, 9, 8, 4, 6, 2},
+					{9, 3, 4, 8, 2, 6, 7, 1, 5},
+					{8, 2, 6, 7, 1, 5, 9, 3, 4},
+					{7, 1, 5, 9, 3, 4, 8, 2, 6},
+					{6, 8, 3, 2, 4, 9, 1, 5, 7},
+					{2, 4, 9, 1, 5, 7, 6, 8, 3},
+					{1, 5, 7, 6, 8, 3, 2, 4, 9},
+				}),
+			},
+			want: 1,
+		},
+		{
+			name: "unique puzzle",
+			args: args{
+				limit: 2,
+				board: board.FromNumbers([9][9]int{
+					{0, 0, 0, 4, 0, 1, 6, 0, 0},
+					{0, 0, 2, 5, 0, 0, 0, 0, 3},
+					{5, 3, 0, 9, 0, 0, 0, 0, 0},
+					{0, 0, 6, 0, 0, 0, 3, 0, 0},
+					{0, 0, 0, 0, 8, 0, 0, 4, 1},
+					{4, 0, 5, 0, 0, 0, 0, 0, 7},
+					{2, 5, 8, 0, 0, 9, 0, 0, 0},
+					{0, 0, 9, 0, 4, 2, 0, 0, 0},
+					{0, 0, 0, 7, 0, 0, 0, 0, 0},
+				}),
+			},
+			want: 1,
+		},
+		{
+			name: "two solutions",
+			args: args{
+				limit: 10,
+				board: board.FromNumbers([9][9]int{
+					{0, 0, 8, 4, 6, 2, 5, 7, 1},
+					{4, 6, 2, 5, 7, 1, 3, 9, 8},
+					{5, 7, 1, 3, 9, 8, 4, 6, 2},
+					{0, 0, 4, 8, 2, 6, 7, 1, 5},
+					{8, 2, 6, 7, 1, 5, 9, 3, 4},
+					{7, 1, 5, 9, 3, 4, 8, 2, 6},
+					{6, 8, 3, 2, 4, 9, 1, 5, 7},
+					{2, 4, 9, 1, 5, 7, 6, 8, 3},
+					{1, 5, 7, 6, 8, 3, 2, 4, 9},
+				}),
+			},
+			want: 2,
+		},
+		{
+			name: "limit reached",
+			args: args{
+				limit: 5,
+				board: &board.SudokuBoard{},
+			},
+			want: 5,
+		},
+		{
+			name: "invalid givens",
+			args: args{
+				limit: 2,
+				board: board.FromNumbers([9][9]int{
+					{1, 1, 0, 0, 0, 0, 0, 0, 0},
+				}),
+			},
+			want: 0,
+		},
+		{
+			name: "constraint removes solutions",
+			args: args{
+				limit: 2,
+				board: board.FromNumbers([9][9]int{
+					{3, 9, 8, 4, 6, 2, 5, 7, 1},
+					{4, 6, 2, 5, 7, 1, 3, 9, 8},
+					{5, 7, 1, 3, 9, 8, 4, 6, 2},
+					{9, 3, 4, 8, 2, 6, 7, 1, 5},
+					{8, 2, 6, 7, 1, 5, 9, 3, 4},
+					{7, 1, 5, 9, 3, 4, 8, 2, 6},
+					{6, 8, 3, 2, 4, 9, 1, 5, 7},
+					{2, 4, 9, 1, 5, 7, 6, 8, 3},
+					{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				}).AddConstraints(board.NonConsecutiveConstraint{}),
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := tt.args.board.String()
+			if got := CountSolutions(tt.args.board, tt.args.limit); got != tt.want {
+				t.Errorf("CountSolutions() = %v, want %v", got, tt.want)
+			}
+			if after := tt.args.board.String(); after != before {
+				t.Errorf("CountSolutions() modified the board")
+			}
+		})
+	}
+}
+
+func TestSolveByGuessing_Constraints(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints []board.Constraint
+	}{
+		{
+			name:        "anti-knight",
+			constraints: []board.Constraint{board.AntiKnightConstraint{}},
+		},
+		{
+			name:        "anti-king",
+			constraints: []board.Constraint{board.AntiKingConstraint{}},
+		},
+		{
+			name:        "non-consecutive",
+			constraints: []board.Constraint{board.NonConsecutiveConstraint{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := (&board.SudokuBoard{}).AddConstraints(tt.constraints...)
+			SolveByGuessing(DefaultGuessConfig(), b)
+			if !board.IsSolved(b) {
+				t.Errorf("SolveByGuessing() did not solve the board\n%v", b)
+			}
+		})
+	}
+}
+
+func TestSolveFromCandidates(t *testing.T) {
+	solution := board.FromNumbers([9][9]int{
+		{3, 9, 8, 4, 6, 2, 5, 7, 1},
+		{4, 6, 2, 5, 7, 1, 3, 9, 8},
+		{5, 7, 1, 3, 9, 8, 4, 6, 2},
+		{9, 3, 4, 8, 2, 6, 7, 1, 5},
+		{8, 2, 6, 7, 1, 5, 9, 3, 4},
+		{7, 1, 5, 9, 3, 4, 8, 2, 6},
+		{6, 8, 3, 2, 4, 9, 1, 5, 7},
+		{2, 4, 9, 1, 5, 7, 6, 8, 3},
+		{1, 5, 7, 6, 8, 3, 2, 4, 9},
+	})
+	// every cell allows its own number, two rows allow anything and a few cells allow a wrong number as well
+	candidates := board.CandidateGrid{}
+	for x := 0; x < 9; x++ {
+		for y := 0; y < 9; y++ {
+			candidates.Allow(x, y, solution.GetAt(x, y))
+			if y == 1 || y == 6 {
+				candidates[x][y] = [9]bool{true, true, true, true, true, true, true, true, true}
+			}
+		}
+	}
+	candidates.Allow(0, 0, 4)
+	candidates.Allow(8, 8, 1)
+
+	if got := CountSolutionsFromCandidates(&board.SudokuBoard{}, candidates, 2); got != 1 {
+		t.Fatalf("CountSolutionsFromCandidates() = %d, want 1", got)
+	}
+
+	b := &board.SudokuBoard{}
+	steps := SolveByStrategiesFromCandidates(b, candidates)
+	if !reflect.DeepEqual(b, solution) {
+		t.Errorf("SolveByStrategiesFromCandidates() = \n%v, want \n%v", b, solution)
+	}
+	for _, step := range steps {
+		if step.name == StrategyNamePsychicStrategy {
+			t.Errorf("SolveByStrategiesFromCandidates() needed to guess")
+		}
+	}
+
+	b = &board.SudokuBoard{}
+	SolveByGuessingFromCandidates(DefaultGuessConfig(), b, candidates)
+	if !reflect.DeepEqual(b, solution) {
+		t.Errorf("SolveByGuessingFromCandidates() = \n%v, want \n%v", b, solution)
+	}
+
+	candidates[4][4] = [9]bool{}
+	if got := CountSolutionsFromCandidates(&board.SudokuBoard{}, candidates, 2); got != 0 {
+		t.Errorf("CountSolutionsFromCandidates() = %d with an empty cell, want 0", got)
+	}
+	b = &board.SudokuBoard{}
+	SolveByStrategiesFromCandidates(b, candidates)
+	if board.IsSolved(b) || b.GetAt(4, 4) != 0 {
+		t.Errorf("SolveByStrategiesFromCandidates() = \n%v with an empty cell, want it unsolved", b)
+	}
+}
+
+func TestFindSolutions(t *testing.T) {
+	puzzle, _ := board.ParseLine("53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79")
+	tests := []struct {
+		name  string
+		b     *board.SudokuBoard
+		limit int
+		want  int
+	}{
+		{name: "unique", b: puzzle, limit: 5, want: 1},
+		{name: "empty", b: &board.SudokuBoard{}, limit: 3, want: 3},
+		{name: "no limit", b: puzzle, limit: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := board.FormatLine(tt.b)
+			got := FindSolutions(tt.b, tt.limit)
+			if len(got) != tt.want {
+				t.Fatalf("FindSolutions() found %d solutions, want %d", len(got), tt.want)
+			}
+			seen := map[string]bool{}
+			for _, s := range got {
+				if !board.IsSolved(s) || seen[board.FormatLine(s)] {
+					t.Errorf("FindSolutions() gave a wrong or repeated solution %s", board.FormatLine(s))
+				}
+				seen[board.FormatLine(s)] = true
+			}
+			if board.FormatLine(tt.b) != before {
+				t.Errorf("FindSolutions() changed the board")
+			}
+		})
+	}
+}
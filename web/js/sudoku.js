@@ -62,7 +62,7 @@ function FillBoard(bdata) {
 
     for (let x = 0; x < 9; x++) {
         for (let y = 0; y < 9; y++) {
-            let cell = bdata.board[y][x];
+            let cell = bdata.board.cells[y][x];
             if (cell === 0) {
                 continue;
             }